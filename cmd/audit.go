@@ -0,0 +1,92 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+var (
+	auditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Work with audit logs",
+	}
+
+	auditVerifyCmd = &cobra.Command{
+		Use:   "verify FILE...",
+		Short: "Verify that audit logs have not been tampered with",
+		Long: `Verify that audit logs have not been tampered with.
+
+The log has to start with its first event. Rotated files continue each other, pass them oldest
+first followed by the current file:
+
+  audit verify audit.log.2 audit.log.1 audit.log
+
+Once the oldest rotated files have been removed, the remaining ones can only be verified from the
+sequence number they start at:
+
+  audit verify --allow-rotated-head audit.log.10 ... audit.log
+
+Logs written with --audit-key-file are verified with the same key:
+
+  audit verify --key-file /etc/bitwarden-sdk-server/audit-key audit.log`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runAuditVerifyCmd,
+	}
+
+	auditVerifyArgs struct {
+		keyFile          string
+		allowRotatedHead bool
+	}
+)
+
+func init() {
+	auditVerifyCmd.Flags().StringVar(&auditVerifyArgs.keyFile, "key-file", "", "--key-file /etc/bitwarden-sdk-server/audit-key; file containing the key the audit log was written with using --audit-key-file")
+	auditVerifyCmd.Flags().BoolVar(&auditVerifyArgs.allowRotatedHead, "allow-rotated-head", false, "--allow-rotated-head; accept logs not starting with the first event, events before it are not verified")
+	auditCmd.AddCommand(auditVerifyCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAuditVerifyCmd(cmd *cobra.Command, args []string) error {
+	opts := audit.VerifyOptions{AllowRotatedHead: auditVerifyArgs.allowRotatedHead}
+	if auditVerifyArgs.keyFile != "" {
+		key, err := audit.ReadKey(auditVerifyArgs.keyFile)
+		if err != nil {
+			return err
+		}
+		opts.Key = key
+	}
+
+	res, err := audit.VerifyFiles(opts, args...)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(out, "verified %d events\n", res.Events)
+	if res.Events > 0 {
+		_, _ = fmt.Fprintf(out, "first: seq %d, previous hash %q\n", res.FirstSeq, res.FirstPrevHash)
+		if res.FirstSeq != 1 {
+			_, _ = fmt.Fprintf(out, "events before seq %d are not verified\n", res.FirstSeq)
+		}
+		_, _ = fmt.Fprintf(out, "last:  seq %d, hash %q\n", res.LastSeq, res.LastHash)
+	}
+
+	return nil
+}
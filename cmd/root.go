@@ -17,6 +17,7 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -26,7 +27,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/memory"
 	"github.com/external-secrets/bitwarden-sdk-server/pkg/server"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/tracing"
 )
 
 var (
@@ -37,7 +41,11 @@ var (
 	}
 
 	rootArgs struct {
-		server server.Config
+		server  server.Config
+		tracing tracing.Config
+		backend string
+		// auditKeyFile is the file the key of the audit log is read from.
+		auditKeyFile string
 	}
 )
 
@@ -49,11 +57,80 @@ func init() {
 	flag.StringVar(&rootArgs.server.KeyFile, "key-file", "/certs/key.pem", "--key-file /certs/key.pem")
 	flag.StringVar(&rootArgs.server.CertFile, "cert-file", "/certs/cert.pem", "--cert-file /certs/cert.pem")
 	flag.StringVar(&rootArgs.server.Addr, "hostname", ":9998", "--hostname :9998")
+	flag.StringVar(&rootArgs.backend, "backend", backendSDK, "--backend memory; one of sdk, serving Bitwarden, or memory, serving an in-memory store accepting any access token")
+	// Session Configs
+	flag.DurationVar(&rootArgs.server.SessionTTL, "session-ttl", 0, "--session-ttl 5m; reuse logged in clients until idle for this long, 0 disables reuse")
+	flag.IntVar(&rootArgs.server.SessionMaxSize, "session-max-size", 100, "--session-max-size 100; maximum number of cached sessions")
+	// Bitwarden URL Configs
+	flag.StringSliceVar(&rootArgs.server.AllowedHosts, "allowed-hosts", nil, "--allowed-hosts vault.bitwarden.eu,*.example.com; hosts allowed in the Warden-Api-Url and Warden-Identity-Url headers, empty allows any")
+	flag.BoolVar(&rootArgs.server.RequireHTTPS, "require-https", false, "--require-https; reject Warden-Api-Url and Warden-Identity-Url headers not using https")
+	flag.BoolVar(&rootArgs.server.PinURLs, "pin-urls", false, "--pin-urls; ignore the Warden-Api-Url and Warden-Identity-Url headers and use --api-url and --identity-url")
+	flag.StringVar(&rootArgs.server.APIURL, "api-url", "", "--api-url https://api.bitwarden.com; api url used with --pin-urls, defaults to the Bitwarden cloud")
+	flag.StringVar(&rootArgs.server.IdentityURL, "identity-url", "", "--identity-url https://identity.bitwarden.com; identity url used with --pin-urls, defaults to the Bitwarden cloud")
+	// State Configs
+	flag.StringVar(&rootArgs.server.StateDir, "state-dir", "", "--state-dir /var/lib/bitwarden-sdk-server; directory all Warden-State-Path values are resolved in")
+	flag.BoolVar(&rootArgs.server.DeriveStatePath, "derive-state-path", false, "--derive-state-path; derive the state path from the access token when Warden-State-Path is not set, requires --state-dir")
+	// Cache Configs
+	flag.DurationVar(&rootArgs.server.SecretCacheTTL, "secret-cache-ttl", 0, "--secret-cache-ttl 30s; serve fetched secrets from memory for this long, 0 disables the cache")
+	flag.DurationVar(&rootArgs.server.SecretCacheStaleTTL, "secret-cache-stale-ttl", 0, "--secret-cache-stale-ttl 1m; serve expired secrets for this long while refreshing them in the background")
+	// Bulk Configs
+	flag.IntVar(&rootArgs.server.BulkConcurrency, "bulk-concurrency", 4, "--bulk-concurrency 4; number of items of a bulk request sent to Bitwarden at once")
+	// Metrics Configs
+	flag.StringVar(&rootArgs.server.MetricsAddr, "metrics-addr", "", "--metrics-addr :9999; serve /metrics on a separate http listener, empty serves it next to the api")
+	// gRPC Configs
+	flag.StringVar(&rootArgs.server.GRPCAddr, "grpc-addr", "", "--grpc-addr :9997; serve the grpc api on this address using the tls configuration of the rest api, empty disables grpc")
+	flag.BoolVar(&rootArgs.server.GRPCReflection, "grpc-reflection", false, "--grpc-reflection; serve the schema of the grpc api through the reflection service, for tools like grpcurl")
+	// Audit Configs
+	flag.StringVar(&rootArgs.server.Audit.Path, "audit-log", "", "--audit-log /var/log/bitwarden-sdk-server/audit.log; file secret access and changes are recorded in, - for stdout, empty disables auditing")
+	flag.Int64Var(&rootArgs.server.Audit.MaxSize, "audit-max-size", 100*1024*1024, "--audit-max-size 104857600; size in bytes after which the audit log is rotated, 0 disables rotation")
+	flag.IntVar(&rootArgs.server.Audit.MaxBackups, "audit-max-backups", 10, "--audit-max-backups 10; number of rotated audit logs to keep")
+	flag.StringVar(&rootArgs.auditKeyFile, "audit-key-file", "", "--audit-key-file /etc/bitwarden-sdk-server/audit-key; file containing the key the audit log is chained with as HMACs, so it can't be rewritten without the key")
+	// Tracing Configs
+	flag.StringVar(&rootArgs.tracing.Exporter, "tracing-exporter", tracing.ExporterNone, "--tracing-exporter otlp; one of none, stdout, file or otlp")
+	flag.StringVar(&rootArgs.tracing.File, "tracing-file", "", "--tracing-file /tmp/traces.json; file spans are appended to by the file exporter")
+	flag.StringVar(&rootArgs.tracing.Endpoint, "tracing-endpoint", "", "--tracing-endpoint http://localhost:4318; otlp/http collector url, defaults to the OTEL_EXPORTER_OTLP_* environment variables")
+	flag.Float64Var(&rootArgs.tracing.SampleRatio, "tracing-sample-ratio", 1, "--tracing-sample-ratio 0.1; ratio of new traces that are sampled, traces started by callers follow their sampling decision")
 }
 
 const timeout = 15 * time.Second
 
+// Supported backends.
+const (
+	backendSDK    = "sdk"
+	backendMemory = "memory"
+)
+
 func runServeCmd(_ *cobra.Command, _ []string) error {
+	switch rootArgs.backend {
+	case backendSDK:
+	case backendMemory:
+		slog.Warn("serving an in-memory store, secrets are lost on shutdown and any access token is accepted")
+		rootArgs.server.Backend = memory.New()
+	default:
+		return fmt.Errorf("unknown backend %q, must be one of %s or %s", rootArgs.backend, backendSDK, backendMemory)
+	}
+
+	if err := rootArgs.server.Validate(); err != nil {
+		return err
+	}
+
+	if rootArgs.server.Audit.Path == audit.Stdout && rootArgs.tracing.Exporter == tracing.ExporterStdout {
+		return errors.New("--audit-log - can't be combined with --tracing-exporter stdout, both write to stdout")
+	}
+
+	if rootArgs.auditKeyFile != "" {
+		key, err := audit.ReadKey(rootArgs.auditKeyFile)
+		if err != nil {
+			return err
+		}
+		rootArgs.server.Audit.Key = key
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), rootArgs.tracing)
+	if err != nil {
+		return err
+	}
+
 	svr := server.NewServer(rootArgs.server)
 	go func() {
 		if err := svr.Run(context.Background()); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -83,6 +160,10 @@ func runServeCmd(_ *cobra.Command, _ []string) error {
 		os.Exit(1)
 	}
 
+	if err := shutdownTracing(context.Background()); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+
 	slog.Info("all done. Goodbye.")
 
 	done <- struct{}{}
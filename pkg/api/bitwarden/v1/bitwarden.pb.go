@@ -0,0 +1,1546 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: bitwarden/v1/bitwarden.proto
+
+package bitwardenv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Secret struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	ProjectId      *string                `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3,oneof" json:"project_id,omitempty"`
+	Key            string                 `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	Value          string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	Note           string                 `protobuf:"bytes,6,opt,name=note,proto3" json:"note,omitempty"`
+	CreationDate   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	RevisionDate   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=revision_date,json=revisionDate,proto3" json:"revision_date,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Secret) Reset() {
+	*x = Secret{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Secret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Secret) ProtoMessage() {}
+
+func (x *Secret) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
+func (*Secret) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Secret) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Secret) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *Secret) GetProjectId() string {
+	if x != nil && x.ProjectId != nil {
+		return *x.ProjectId
+	}
+	return ""
+}
+
+func (x *Secret) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Secret) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Secret) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *Secret) GetCreationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationDate
+	}
+	return nil
+}
+
+func (x *Secret) GetRevisionDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevisionDate
+	}
+	return nil
+}
+
+type SecretIdentifier struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	ProjectIds     []string               `protobuf:"bytes,3,rep,name=project_ids,json=projectIds,proto3" json:"project_ids,omitempty"`
+	Key            string                 `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SecretIdentifier) Reset() {
+	*x = SecretIdentifier{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecretIdentifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretIdentifier) ProtoMessage() {}
+
+func (x *SecretIdentifier) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretIdentifier.ProtoReflect.Descriptor instead.
+func (*SecretIdentifier) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SecretIdentifier) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SecretIdentifier) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *SecretIdentifier) GetProjectIds() []string {
+	if x != nil {
+		return x.ProjectIds
+	}
+	return nil
+}
+
+func (x *SecretIdentifier) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type GetSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSecretRequest) Reset() {
+	*x = GetSecretRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSecretRequest) ProtoMessage() {}
+
+func (x *GetSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSecretRequest.ProtoReflect.Descriptor instead.
+func (*GetSecretRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetSecretsByIDsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSecretsByIDsRequest) Reset() {
+	*x = GetSecretsByIDsRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSecretsByIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSecretsByIDsRequest) ProtoMessage() {}
+
+func (x *GetSecretsByIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSecretsByIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetSecretsByIDsRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSecretsByIDsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetSecretsByIDsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secrets       []*Secret              `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSecretsByIDsResponse) Reset() {
+	*x = GetSecretsByIDsResponse{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSecretsByIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSecretsByIDsResponse) ProtoMessage() {}
+
+func (x *GetSecretsByIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSecretsByIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetSecretsByIDsResponse) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSecretsByIDsResponse) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+// ListSecretsRequest lists the secrets of an organization. Secrets have to match every filter
+// that is set.
+type ListSecretsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	// key_prefix matches keys starting with the prefix.
+	KeyPrefix string `protobuf:"bytes,2,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"`
+	// key_pattern matches keys against a glob pattern, like `db-*`.
+	KeyPattern string `protobuf:"bytes,3,opt,name=key_pattern,json=keyPattern,proto3" json:"key_pattern,omitempty"`
+	// key_regex matches keys against an unanchored regular expression.
+	KeyRegex string `protobuf:"bytes,4,opt,name=key_regex,json=keyRegex,proto3" json:"key_regex,omitempty"`
+	// project_ids matches secrets in any of the projects.
+	ProjectIds []string `protobuf:"bytes,5,rep,name=project_ids,json=projectIds,proto3" json:"project_ids,omitempty"`
+	// with_values also returns the matching secrets including their values.
+	WithValues bool `protobuf:"varint,6,opt,name=with_values,json=withValues,proto3" json:"with_values,omitempty"`
+	// limit is the maximum number of secrets returned, all are returned if unset.
+	Limit int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// continuation_token is the token returned with the previous page, to get the next one.
+	ContinuationToken string `protobuf:"bytes,8,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	// sort is `key` (the default) or `revisionDate`, optionally prefixed with `-` to sort in
+	// descending order.
+	Sort          string `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsRequest) Reset() {
+	*x = ListSecretsRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsRequest) ProtoMessage() {}
+
+func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSecretsRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *ListSecretsRequest) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+func (x *ListSecretsRequest) GetKeyPattern() string {
+	if x != nil {
+		return x.KeyPattern
+	}
+	return ""
+}
+
+func (x *ListSecretsRequest) GetKeyRegex() string {
+	if x != nil {
+		return x.KeyRegex
+	}
+	return ""
+}
+
+func (x *ListSecretsRequest) GetProjectIds() []string {
+	if x != nil {
+		return x.ProjectIds
+	}
+	return nil
+}
+
+func (x *ListSecretsRequest) GetWithValues() bool {
+	if x != nil {
+		return x.WithValues
+	}
+	return false
+}
+
+func (x *ListSecretsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListSecretsRequest) GetContinuationToken() string {
+	if x != nil {
+		return x.ContinuationToken
+	}
+	return ""
+}
+
+func (x *ListSecretsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ListSecretsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Secrets []*SecretIdentifier    `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	// values contains the matching secrets if with_values was set.
+	Values []*Secret `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	// continuation_token is set if there are more secrets.
+	ContinuationToken string `protobuf:"bytes,3,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListSecretsResponse) Reset() {
+	*x = ListSecretsResponse{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsResponse) ProtoMessage() {}
+
+func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListSecretsResponse) GetSecrets() []*SecretIdentifier {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *ListSecretsResponse) GetValues() []*Secret {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ListSecretsResponse) GetContinuationToken() string {
+	if x != nil {
+		return x.ContinuationToken
+	}
+	return ""
+}
+
+type SyncSecretsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	LastSyncedDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_synced_date,json=lastSyncedDate,proto3" json:"last_synced_date,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncSecretsRequest) Reset() {
+	*x = SyncSecretsRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncSecretsRequest) ProtoMessage() {}
+
+func (x *SyncSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncSecretsRequest.ProtoReflect.Descriptor instead.
+func (*SyncSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SyncSecretsRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *SyncSecretsRequest) GetLastSyncedDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSyncedDate
+	}
+	return nil
+}
+
+type SyncSecretsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HasChanges    bool                   `protobuf:"varint,1,opt,name=has_changes,json=hasChanges,proto3" json:"has_changes,omitempty"`
+	Secrets       []*Secret              `protobuf:"bytes,2,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncSecretsResponse) Reset() {
+	*x = SyncSecretsResponse{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncSecretsResponse) ProtoMessage() {}
+
+func (x *SyncSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncSecretsResponse.ProtoReflect.Descriptor instead.
+func (*SyncSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SyncSecretsResponse) GetHasChanges() bool {
+	if x != nil {
+		return x.HasChanges
+	}
+	return false
+}
+
+func (x *SyncSecretsResponse) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+type CreateSecretRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	ProjectIds     []string               `protobuf:"bytes,2,rep,name=project_ids,json=projectIds,proto3" json:"project_ids,omitempty"`
+	Key            string                 `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Value          string                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Note           string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateSecretRequest) Reset() {
+	*x = CreateSecretRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSecretRequest) ProtoMessage() {}
+
+func (x *CreateSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSecretRequest.ProtoReflect.Descriptor instead.
+func (*CreateSecretRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateSecretRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetProjectIds() []string {
+	if x != nil {
+		return x.ProjectIds
+	}
+	return nil
+}
+
+func (x *CreateSecretRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type UpdateSecretRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	ProjectIds     []string               `protobuf:"bytes,3,rep,name=project_ids,json=projectIds,proto3" json:"project_ids,omitempty"`
+	Key            string                 `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	Value          string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	Note           string                 `protobuf:"bytes,6,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateSecretRequest) Reset() {
+	*x = UpdateSecretRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSecretRequest) ProtoMessage() {}
+
+func (x *UpdateSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSecretRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSecretRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateSecretRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSecretRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *UpdateSecretRequest) GetProjectIds() []string {
+	if x != nil {
+		return x.ProjectIds
+	}
+	return nil
+}
+
+func (x *UpdateSecretRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UpdateSecretRequest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *UpdateSecretRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type DeleteSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSecretsRequest) Reset() {
+	*x = DeleteSecretsRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSecretsRequest) ProtoMessage() {}
+
+func (x *DeleteSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSecretsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteSecretsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type Project struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreationDate   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	RevisionDate   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=revision_date,json=revisionDate,proto3" json:"revision_date,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Project) Reset() {
+	*x = Project{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Project) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Project) ProtoMessage() {}
+
+func (x *Project) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Project.ProtoReflect.Descriptor instead.
+func (*Project) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Project) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Project) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *Project) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Project) GetCreationDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationDate
+	}
+	return nil
+}
+
+func (x *Project) GetRevisionDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevisionDate
+	}
+	return nil
+}
+
+type GetProjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProjectRequest) Reset() {
+	*x = GetProjectRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectRequest) ProtoMessage() {}
+
+func (x *GetProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectRequest.ProtoReflect.Descriptor instead.
+func (*GetProjectRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetProjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListProjectsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	// limit is the maximum number of projects returned, all are returned if unset.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// continuation_token is the token returned with the previous page, to get the next one.
+	ContinuationToken string `protobuf:"bytes,3,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	// sort is `name` (the default) or `revisionDate`, optionally prefixed with `-` to sort in
+	// descending order.
+	Sort          string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProjectsRequest) Reset() {
+	*x = ListProjectsRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProjectsRequest) ProtoMessage() {}
+
+func (x *ListProjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProjectsRequest.ProtoReflect.Descriptor instead.
+func (*ListProjectsRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListProjectsRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *ListProjectsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListProjectsRequest) GetContinuationToken() string {
+	if x != nil {
+		return x.ContinuationToken
+	}
+	return ""
+}
+
+func (x *ListProjectsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ListProjectsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Projects []*Project             `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`
+	// continuation_token is set if there are more projects.
+	ContinuationToken string `protobuf:"bytes,2,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListProjectsResponse) Reset() {
+	*x = ListProjectsResponse{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProjectsResponse) ProtoMessage() {}
+
+func (x *ListProjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProjectsResponse.ProtoReflect.Descriptor instead.
+func (*ListProjectsResponse) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListProjectsResponse) GetProjects() []*Project {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+func (x *ListProjectsResponse) GetContinuationToken() string {
+	if x != nil {
+		return x.ContinuationToken
+	}
+	return ""
+}
+
+type CreateProjectRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateProjectRequest) Reset() {
+	*x = CreateProjectRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProjectRequest) ProtoMessage() {}
+
+func (x *CreateProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProjectRequest.ProtoReflect.Descriptor instead.
+func (*CreateProjectRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateProjectRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *CreateProjectRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UpdateProjectRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrganizationId string                 `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateProjectRequest) Reset() {
+	*x = UpdateProjectRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProjectRequest) ProtoMessage() {}
+
+func (x *UpdateProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProjectRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProjectRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateProjectRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProjectRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *UpdateProjectRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteProjectsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProjectsRequest) Reset() {
+	*x = DeleteProjectsRequest{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProjectsRequest) ProtoMessage() {}
+
+func (x *DeleteProjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProjectsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProjectsRequest) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteProjectsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// DeleteResponse reports the result of deleting every requested ID. Deleting single IDs can fail
+// without failing the whole call.
+type DeleteResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Results       []*DeleteResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteResponse) GetResults() []*DeleteResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type DeleteResponse_Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Error         *string                `protobuf:"bytes,2,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse_Result) Reset() {
+	*x = DeleteResponse_Result{}
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse_Result) ProtoMessage() {}
+
+func (x *DeleteResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_bitwarden_v1_bitwarden_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse_Result.ProtoReflect.Descriptor instead.
+func (*DeleteResponse_Result) Descriptor() ([]byte, []int) {
+	return file_bitwarden_v1_bitwarden_proto_rawDescGZIP(), []int{19, 0}
+}
+
+func (x *DeleteResponse_Result) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteResponse_Result) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+var File_bitwarden_v1_bitwarden_proto protoreflect.FileDescriptor
+
+const file_bitwarden_v1_bitwarden_proto_rawDesc = "" +
+	"\n" +
+	"\x1cbitwarden/v1/bitwarden.proto\x12\fbitwarden.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb2\x02\n" +
+	"\x06Secret\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12\"\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\tH\x00R\tprojectId\x88\x01\x01\x12\x10\n" +
+	"\x03key\x18\x04 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\tR\x05value\x12\x12\n" +
+	"\x04note\x18\x06 \x01(\tR\x04note\x12?\n" +
+	"\rcreation_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\fcreationDate\x12?\n" +
+	"\rrevision_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\frevisionDateB\r\n" +
+	"\v_project_id\"~\n" +
+	"\x10SecretIdentifier\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12\x1f\n" +
+	"\vproject_ids\x18\x03 \x03(\tR\n" +
+	"projectIds\x12\x10\n" +
+	"\x03key\x18\x04 \x01(\tR\x03key\"\"\n" +
+	"\x10GetSecretRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x16GetSecretsByIDsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"I\n" +
+	"\x17GetSecretsByIDsResponse\x12.\n" +
+	"\asecrets\x18\x01 \x03(\v2\x14.bitwarden.v1.SecretR\asecrets\"\xb5\x02\n" +
+	"\x12ListSecretsRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x02 \x01(\tR\tkeyPrefix\x12\x1f\n" +
+	"\vkey_pattern\x18\x03 \x01(\tR\n" +
+	"keyPattern\x12\x1b\n" +
+	"\tkey_regex\x18\x04 \x01(\tR\bkeyRegex\x12\x1f\n" +
+	"\vproject_ids\x18\x05 \x03(\tR\n" +
+	"projectIds\x12\x1f\n" +
+	"\vwith_values\x18\x06 \x01(\bR\n" +
+	"withValues\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12-\n" +
+	"\x12continuation_token\x18\b \x01(\tR\x11continuationToken\x12\x12\n" +
+	"\x04sort\x18\t \x01(\tR\x04sort\"\xac\x01\n" +
+	"\x13ListSecretsResponse\x128\n" +
+	"\asecrets\x18\x01 \x03(\v2\x1e.bitwarden.v1.SecretIdentifierR\asecrets\x12,\n" +
+	"\x06values\x18\x02 \x03(\v2\x14.bitwarden.v1.SecretR\x06values\x12-\n" +
+	"\x12continuation_token\x18\x03 \x01(\tR\x11continuationToken\"\x83\x01\n" +
+	"\x12SyncSecretsRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x12D\n" +
+	"\x10last_synced_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastSyncedDate\"f\n" +
+	"\x13SyncSecretsResponse\x12\x1f\n" +
+	"\vhas_changes\x18\x01 \x01(\bR\n" +
+	"hasChanges\x12.\n" +
+	"\asecrets\x18\x02 \x03(\v2\x14.bitwarden.v1.SecretR\asecrets\"\x9b\x01\n" +
+	"\x13CreateSecretRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x12\x1f\n" +
+	"\vproject_ids\x18\x02 \x03(\tR\n" +
+	"projectIds\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\"\xab\x01\n" +
+	"\x13UpdateSecretRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12\x1f\n" +
+	"\vproject_ids\x18\x03 \x03(\tR\n" +
+	"projectIds\x12\x10\n" +
+	"\x03key\x18\x04 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\tR\x05value\x12\x12\n" +
+	"\x04note\x18\x06 \x01(\tR\x04note\"(\n" +
+	"\x14DeleteSecretsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\xd8\x01\n" +
+	"\aProject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12?\n" +
+	"\rcreation_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreationDate\x12?\n" +
+	"\rrevision_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\frevisionDate\"#\n" +
+	"\x11GetProjectRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x97\x01\n" +
+	"\x13ListProjectsRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12-\n" +
+	"\x12continuation_token\x18\x03 \x01(\tR\x11continuationToken\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\"x\n" +
+	"\x14ListProjectsResponse\x121\n" +
+	"\bprojects\x18\x01 \x03(\v2\x15.bitwarden.v1.ProjectR\bprojects\x12-\n" +
+	"\x12continuation_token\x18\x02 \x01(\tR\x11continuationToken\"S\n" +
+	"\x14CreateProjectRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"c\n" +
+	"\x14UpdateProjectRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0forganization_id\x18\x02 \x01(\tR\x0eorganizationId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\")\n" +
+	"\x15DeleteProjectsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\x8e\x01\n" +
+	"\x0eDeleteResponse\x12=\n" +
+	"\aresults\x18\x01 \x03(\v2#.bitwarden.v1.DeleteResponse.ResultR\aresults\x1a=\n" +
+	"\x06Result\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\x05error\x18\x02 \x01(\tH\x00R\x05error\x88\x01\x01B\b\n" +
+	"\x06_error2\xc0\x04\n" +
+	"\x0eSecretsService\x12A\n" +
+	"\tGetSecret\x12\x1e.bitwarden.v1.GetSecretRequest\x1a\x14.bitwarden.v1.Secret\x12^\n" +
+	"\x0fGetSecretsByIDs\x12$.bitwarden.v1.GetSecretsByIDsRequest\x1a%.bitwarden.v1.GetSecretsByIDsResponse\x12R\n" +
+	"\vListSecrets\x12 .bitwarden.v1.ListSecretsRequest\x1a!.bitwarden.v1.ListSecretsResponse\x12R\n" +
+	"\vSyncSecrets\x12 .bitwarden.v1.SyncSecretsRequest\x1a!.bitwarden.v1.SyncSecretsResponse\x12G\n" +
+	"\fCreateSecret\x12!.bitwarden.v1.CreateSecretRequest\x1a\x14.bitwarden.v1.Secret\x12G\n" +
+	"\fUpdateSecret\x12!.bitwarden.v1.UpdateSecretRequest\x1a\x14.bitwarden.v1.Secret\x12Q\n" +
+	"\rDeleteSecrets\x12\".bitwarden.v1.DeleteSecretsRequest\x1a\x1c.bitwarden.v1.DeleteResponse2\x9b\x03\n" +
+	"\x0fProjectsService\x12D\n" +
+	"\n" +
+	"GetProject\x12\x1f.bitwarden.v1.GetProjectRequest\x1a\x15.bitwarden.v1.Project\x12U\n" +
+	"\fListProjects\x12!.bitwarden.v1.ListProjectsRequest\x1a\".bitwarden.v1.ListProjectsResponse\x12J\n" +
+	"\rCreateProject\x12\".bitwarden.v1.CreateProjectRequest\x1a\x15.bitwarden.v1.Project\x12J\n" +
+	"\rUpdateProject\x12\".bitwarden.v1.UpdateProjectRequest\x1a\x15.bitwarden.v1.Project\x12S\n" +
+	"\x0eDeleteProjects\x12#.bitwarden.v1.DeleteProjectsRequest\x1a\x1c.bitwarden.v1.DeleteResponseBSZQgithub.com/external-secrets/bitwarden-sdk-server/pkg/api/bitwarden/v1;bitwardenv1b\x06proto3"
+
+var (
+	file_bitwarden_v1_bitwarden_proto_rawDescOnce sync.Once
+	file_bitwarden_v1_bitwarden_proto_rawDescData []byte
+)
+
+func file_bitwarden_v1_bitwarden_proto_rawDescGZIP() []byte {
+	file_bitwarden_v1_bitwarden_proto_rawDescOnce.Do(func() {
+		file_bitwarden_v1_bitwarden_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bitwarden_v1_bitwarden_proto_rawDesc), len(file_bitwarden_v1_bitwarden_proto_rawDesc)))
+	})
+	return file_bitwarden_v1_bitwarden_proto_rawDescData
+}
+
+var file_bitwarden_v1_bitwarden_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_bitwarden_v1_bitwarden_proto_goTypes = []any{
+	(*Secret)(nil),                  // 0: bitwarden.v1.Secret
+	(*SecretIdentifier)(nil),        // 1: bitwarden.v1.SecretIdentifier
+	(*GetSecretRequest)(nil),        // 2: bitwarden.v1.GetSecretRequest
+	(*GetSecretsByIDsRequest)(nil),  // 3: bitwarden.v1.GetSecretsByIDsRequest
+	(*GetSecretsByIDsResponse)(nil), // 4: bitwarden.v1.GetSecretsByIDsResponse
+	(*ListSecretsRequest)(nil),      // 5: bitwarden.v1.ListSecretsRequest
+	(*ListSecretsResponse)(nil),     // 6: bitwarden.v1.ListSecretsResponse
+	(*SyncSecretsRequest)(nil),      // 7: bitwarden.v1.SyncSecretsRequest
+	(*SyncSecretsResponse)(nil),     // 8: bitwarden.v1.SyncSecretsResponse
+	(*CreateSecretRequest)(nil),     // 9: bitwarden.v1.CreateSecretRequest
+	(*UpdateSecretRequest)(nil),     // 10: bitwarden.v1.UpdateSecretRequest
+	(*DeleteSecretsRequest)(nil),    // 11: bitwarden.v1.DeleteSecretsRequest
+	(*Project)(nil),                 // 12: bitwarden.v1.Project
+	(*GetProjectRequest)(nil),       // 13: bitwarden.v1.GetProjectRequest
+	(*ListProjectsRequest)(nil),     // 14: bitwarden.v1.ListProjectsRequest
+	(*ListProjectsResponse)(nil),    // 15: bitwarden.v1.ListProjectsResponse
+	(*CreateProjectRequest)(nil),    // 16: bitwarden.v1.CreateProjectRequest
+	(*UpdateProjectRequest)(nil),    // 17: bitwarden.v1.UpdateProjectRequest
+	(*DeleteProjectsRequest)(nil),   // 18: bitwarden.v1.DeleteProjectsRequest
+	(*DeleteResponse)(nil),          // 19: bitwarden.v1.DeleteResponse
+	(*DeleteResponse_Result)(nil),   // 20: bitwarden.v1.DeleteResponse.Result
+	(*timestamppb.Timestamp)(nil),   // 21: google.protobuf.Timestamp
+}
+var file_bitwarden_v1_bitwarden_proto_depIdxs = []int32{
+	21, // 0: bitwarden.v1.Secret.creation_date:type_name -> google.protobuf.Timestamp
+	21, // 1: bitwarden.v1.Secret.revision_date:type_name -> google.protobuf.Timestamp
+	0,  // 2: bitwarden.v1.GetSecretsByIDsResponse.secrets:type_name -> bitwarden.v1.Secret
+	1,  // 3: bitwarden.v1.ListSecretsResponse.secrets:type_name -> bitwarden.v1.SecretIdentifier
+	0,  // 4: bitwarden.v1.ListSecretsResponse.values:type_name -> bitwarden.v1.Secret
+	21, // 5: bitwarden.v1.SyncSecretsRequest.last_synced_date:type_name -> google.protobuf.Timestamp
+	0,  // 6: bitwarden.v1.SyncSecretsResponse.secrets:type_name -> bitwarden.v1.Secret
+	21, // 7: bitwarden.v1.Project.creation_date:type_name -> google.protobuf.Timestamp
+	21, // 8: bitwarden.v1.Project.revision_date:type_name -> google.protobuf.Timestamp
+	12, // 9: bitwarden.v1.ListProjectsResponse.projects:type_name -> bitwarden.v1.Project
+	20, // 10: bitwarden.v1.DeleteResponse.results:type_name -> bitwarden.v1.DeleteResponse.Result
+	2,  // 11: bitwarden.v1.SecretsService.GetSecret:input_type -> bitwarden.v1.GetSecretRequest
+	3,  // 12: bitwarden.v1.SecretsService.GetSecretsByIDs:input_type -> bitwarden.v1.GetSecretsByIDsRequest
+	5,  // 13: bitwarden.v1.SecretsService.ListSecrets:input_type -> bitwarden.v1.ListSecretsRequest
+	7,  // 14: bitwarden.v1.SecretsService.SyncSecrets:input_type -> bitwarden.v1.SyncSecretsRequest
+	9,  // 15: bitwarden.v1.SecretsService.CreateSecret:input_type -> bitwarden.v1.CreateSecretRequest
+	10, // 16: bitwarden.v1.SecretsService.UpdateSecret:input_type -> bitwarden.v1.UpdateSecretRequest
+	11, // 17: bitwarden.v1.SecretsService.DeleteSecrets:input_type -> bitwarden.v1.DeleteSecretsRequest
+	13, // 18: bitwarden.v1.ProjectsService.GetProject:input_type -> bitwarden.v1.GetProjectRequest
+	14, // 19: bitwarden.v1.ProjectsService.ListProjects:input_type -> bitwarden.v1.ListProjectsRequest
+	16, // 20: bitwarden.v1.ProjectsService.CreateProject:input_type -> bitwarden.v1.CreateProjectRequest
+	17, // 21: bitwarden.v1.ProjectsService.UpdateProject:input_type -> bitwarden.v1.UpdateProjectRequest
+	18, // 22: bitwarden.v1.ProjectsService.DeleteProjects:input_type -> bitwarden.v1.DeleteProjectsRequest
+	0,  // 23: bitwarden.v1.SecretsService.GetSecret:output_type -> bitwarden.v1.Secret
+	4,  // 24: bitwarden.v1.SecretsService.GetSecretsByIDs:output_type -> bitwarden.v1.GetSecretsByIDsResponse
+	6,  // 25: bitwarden.v1.SecretsService.ListSecrets:output_type -> bitwarden.v1.ListSecretsResponse
+	8,  // 26: bitwarden.v1.SecretsService.SyncSecrets:output_type -> bitwarden.v1.SyncSecretsResponse
+	0,  // 27: bitwarden.v1.SecretsService.CreateSecret:output_type -> bitwarden.v1.Secret
+	0,  // 28: bitwarden.v1.SecretsService.UpdateSecret:output_type -> bitwarden.v1.Secret
+	19, // 29: bitwarden.v1.SecretsService.DeleteSecrets:output_type -> bitwarden.v1.DeleteResponse
+	12, // 30: bitwarden.v1.ProjectsService.GetProject:output_type -> bitwarden.v1.Project
+	15, // 31: bitwarden.v1.ProjectsService.ListProjects:output_type -> bitwarden.v1.ListProjectsResponse
+	12, // 32: bitwarden.v1.ProjectsService.CreateProject:output_type -> bitwarden.v1.Project
+	12, // 33: bitwarden.v1.ProjectsService.UpdateProject:output_type -> bitwarden.v1.Project
+	19, // 34: bitwarden.v1.ProjectsService.DeleteProjects:output_type -> bitwarden.v1.DeleteResponse
+	23, // [23:35] is the sub-list for method output_type
+	11, // [11:23] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_bitwarden_v1_bitwarden_proto_init() }
+func file_bitwarden_v1_bitwarden_proto_init() {
+	if File_bitwarden_v1_bitwarden_proto != nil {
+		return
+	}
+	file_bitwarden_v1_bitwarden_proto_msgTypes[0].OneofWrappers = []any{}
+	file_bitwarden_v1_bitwarden_proto_msgTypes[20].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bitwarden_v1_bitwarden_proto_rawDesc), len(file_bitwarden_v1_bitwarden_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   21,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_bitwarden_v1_bitwarden_proto_goTypes,
+		DependencyIndexes: file_bitwarden_v1_bitwarden_proto_depIdxs,
+		MessageInfos:      file_bitwarden_v1_bitwarden_proto_msgTypes,
+	}.Build()
+	File_bitwarden_v1_bitwarden_proto = out.File
+	file_bitwarden_v1_bitwarden_proto_goTypes = nil
+	file_bitwarden_v1_bitwarden_proto_depIdxs = nil
+}
@@ -0,0 +1,182 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apierror maps errors to HTTP status codes and writes them as structured JSON bodies.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Error codes returned in the body of failed requests.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeBadRequest         = "bad_request"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeNotAcceptable      = "not_acceptable"
+	CodeConflict           = "conflict"
+	CodePreconditionFailed = "precondition_failed"
+	CodeRateLimited        = "rate_limited"
+	CodeBadGateway         = "bad_gateway"
+	CodeGatewayTimeout     = "gateway_timeout"
+	CodeInternal           = "internal"
+)
+
+// Body is the JSON body written for failed requests.
+type Body struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
+	Retryable bool   `json:"retryable"`
+}
+
+// Error is an error with a known status and code.
+type Error struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error written with the given status and code.
+func New(status int, code string, err error) error {
+	return &Error{Status: status, Code: code, Err: err}
+}
+
+// Errorf returns an error written with the given status and code and a formatted message.
+func Errorf(status int, code, format string, args ...any) error {
+	return New(status, code, fmt.Errorf(format, args...))
+}
+
+// classification maps SDK error messages to a status and code.
+type classification struct {
+	status   int
+	code     string
+	patterns []string
+}
+
+// The SDK only returns plain error messages, so the classification is based on their content.
+// The order matters, the first matching entry wins.
+var classifications = []classification{
+	{http.StatusTooManyRequests, CodeRateLimited, []string{"too many requests", "rate limit"}},
+	{http.StatusUnauthorized, CodeUnauthorized, []string{"unauthorized", "invalid_client", "invalid_grant", "invalid access token", "access token is not in a valid format", "token expired", "not authenticated"}},
+	{http.StatusForbidden, CodeForbidden, []string{"forbidden", "access denied", "permission denied"}},
+	{http.StatusNotFound, CodeNotFound, []string{"not found"}},
+	{http.StatusGatewayTimeout, CodeGatewayTimeout, []string{"timed out", "timeout", "deadline exceeded"}},
+	{http.StatusBadGateway, CodeBadGateway, []string{"error sending request", "connection refused", "connection reset", "dns error", "failed to lookup address", "bad gateway", "service unavailable"}},
+}
+
+// statusPattern matches HTTP statuses reported by the SDK, like `404 Not Found`.
+var statusPattern = regexp.MustCompile(`\b([45]\d\d) [A-Z][a-z]`)
+
+// Classify returns the status and code for an error. Errors created by New keep their status and
+// code. Other errors are assumed to come from the SDK and are classified by their message, errors
+// that can't be classified are treated as bad requests.
+func Classify(err error) (status int, code string) {
+	if e, ok := errors.AsType[*Error](err); ok {
+		return e.Status, e.Code
+	}
+
+	if m := statusPattern.FindStringSubmatch(err.Error()); m != nil {
+		upstream, _ := strconv.Atoi(m[1])
+		if status, code := fromUpstreamStatus(upstream); code != "" {
+			return status, code
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, c := range classifications {
+		for _, p := range c.patterns {
+			if strings.Contains(msg, p) {
+				return c.status, c.code
+			}
+		}
+	}
+
+	return http.StatusBadRequest, CodeBadRequest
+}
+
+// fromUpstreamStatus maps a status returned by Bitwarden to the status returned to the caller.
+// Server errors of Bitwarden are reported as bad gateway, they are not errors of this server.
+func fromUpstreamStatus(status int) (int, string) {
+	switch status {
+	case http.StatusUnauthorized:
+		return status, CodeUnauthorized
+	case http.StatusForbidden:
+		return status, CodeForbidden
+	case http.StatusNotFound:
+		return status, CodeNotFound
+	case http.StatusConflict:
+		return status, CodeConflict
+	case http.StatusTooManyRequests:
+		return status, CodeRateLimited
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return http.StatusBadGateway, CodeBadGateway
+	case http.StatusGatewayTimeout:
+		return status, CodeGatewayTimeout
+	}
+
+	return 0, ""
+}
+
+// Retryable reports whether a request failing with the given status may succeed when retried.
+func Retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// Write classifies the error and writes it as a JSON body.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := Classify(err)
+	WriteStatus(w, r, status, code, err.Error())
+}
+
+// WriteStatus writes a JSON error body with the given status, code and message. The request is
+// optional and used to report the request ID.
+func WriteStatus(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	body := Body{
+		Code:      code,
+		Message:   message,
+		Retryable: Retryable(status),
+	}
+	if r != nil {
+		body.RequestID = middleware.GetReqID(r.Context())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
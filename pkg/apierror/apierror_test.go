@@ -0,0 +1,148 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "explicit error",
+			err:            New(http.StatusConflict, CodeConflict, errors.New("duplicate")),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   CodeConflict,
+		},
+		{
+			name:           "wrapped explicit error",
+			err:            fmt.Errorf("context: %w", New(http.StatusInternalServerError, CodeInternal, errors.New("boom"))),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeInternal,
+		},
+		{
+			name:           "status reported by the sdk",
+			err:            errors.New("API error: Received error message from server: [404 Not Found] {\"message\":\"Resource not found.\"}"),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   CodeNotFound,
+		},
+		{
+			name:           "rate limited status",
+			err:            errors.New("API error: [429 Too Many Requests]"),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   CodeRateLimited,
+		},
+		{
+			name:           "service unavailable status",
+			err:            errors.New("API error: [503 Service Unavailable]"),
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   CodeBadGateway,
+		},
+		{
+			name:           "ids containing digits are not statuses",
+			err:            errors.New("failed to get secret f5847eef-2f89-4043-885a-b18a01178e3e"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeBadRequest,
+		},
+		{
+			name:           "not found message",
+			err:            errors.New("failed to get secret: secret not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   CodeNotFound,
+		},
+		{
+			name:           "invalid client",
+			err:            errors.New("bitwarden login: API error: invalid_client"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   CodeUnauthorized,
+		},
+		{
+			name:           "forbidden",
+			err:            errors.New("API error: Access denied"),
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   CodeForbidden,
+		},
+		{
+			name:           "timeout",
+			err:            errors.New("API error: operation timed out"),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   CodeGatewayTimeout,
+		},
+		{
+			name:           "connection error",
+			err:            errors.New("API error: error sending request for url (https://api.bitwarden.com/): dns error"),
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   CodeBadGateway,
+		},
+		{
+			name:           "unknown error",
+			err:            errors.New("API error: something else"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := Classify(tt.err)
+
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var w *httptest.ResponseRecorder
+	handler := middleware.RequestID(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		Write(rw, r, errors.New("API error: [429 Too Many Requests]"))
+	}))
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body Body
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, CodeRateLimited, body.Code)
+	assert.Equal(t, "API error: [429 Too Many Requests]", body.Message)
+	assert.NotEmpty(t, body.RequestID)
+	assert.True(t, body.Retryable)
+}
+
+func TestWriteStatusWithoutRequest(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	WriteStatus(w, nil, http.StatusInternalServerError, CodeInternal, "boom")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"code": "internal", "message": "boom", "retryable": false}`, w.Body.String())
+}
@@ -0,0 +1,291 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records who accessed or changed which secrets as a hash-chained JSON-lines log.
+// Every event contains the hash of the event before it, so removing, reordering or modifying
+// events breaks the chain, which Verify detects. Without a key, anyone able to write the log can
+// recompute the whole chain. With a key, the hashes are HMACs only holders of the key can
+// compute.
+package audit
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stdout is the path writing the audit log to stdout. The chain starts over on every restart, as
+// earlier events can't be read back.
+const Stdout = "-"
+
+// Outcomes of audited requests.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+)
+
+// Event describes a single request handled by the server.
+type Event struct {
+	Seq            uint64    `json:"seq"`
+	Time           time.Time `json:"time"`
+	Operation      string    `json:"operation"`
+	DryRun         bool      `json:"dryRun,omitempty"`
+	SecretIDs      []string  `json:"secretIds,omitempty"`
+	RolledBackIDs  []string  `json:"rolledBackIds,omitempty"`
+	ProjectIDs     []string  `json:"projectIds,omitempty"`
+	OrganizationID string    `json:"organizationId,omitempty"`
+	Caller         string    `json:"caller,omitempty"`
+	ClientIP       string    `json:"clientIp,omitempty"`
+	RequestID      string    `json:"requestId,omitempty"`
+	Outcome        string    `json:"outcome"`
+	Status         int       `json:"status"`
+	PrevHash       string    `json:"prevHash"`
+	Hash           string    `json:"hash"`
+}
+
+// computeHash returns the hash of the event, covering every field except the hash itself. With a
+// key, the hash is an HMAC-SHA256.
+func (e Event) computeHash(key string) (string, error) {
+	e.Hash = ""
+	content, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	if key == "" {
+		sum := sha256.Sum256(content)
+
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write(content)
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Config configures where the audit log is written.
+type Config struct {
+	// Path is the file events are appended to, or Stdout. If empty, auditing is disabled.
+	Path string
+	// MaxSize is the size in bytes after which the file is rotated. Zero disables rotation.
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept next to the current one.
+	MaxBackups int
+	// Key makes the hashes HMACs with this key. If empty, plain SHA-256 hashes are used.
+	Key string
+}
+
+// ReadKey reads the key of an audit log from a file, ignoring trailing line breaks. The key is
+// read from a file so it doesn't show up in process listings.
+func ReadKey(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read audit key: %w", err)
+	}
+
+	key := strings.TrimRight(string(content), "\r\n")
+	if key == "" {
+		return "", fmt.Errorf("audit key file %s is empty", path)
+	}
+
+	return key, nil
+}
+
+// Logger writes hash-chained events. It is safe for concurrent use.
+type Logger struct {
+	mu   sync.Mutex
+	cfg  Config
+	out  io.Writer
+	file *os.File
+	size int64
+	seq  uint64
+	prev string
+	now  func() time.Time
+}
+
+// New creates a logger for the given configuration. If the file already exists, events are
+// appended to it and the chain continues where it stopped.
+func New(cfg Config) (*Logger, error) {
+	l := &Logger{cfg: cfg, now: time.Now}
+	if cfg.Path == Stdout {
+		l.out = os.Stdout
+
+		return l, nil
+	}
+
+	last, err := lastEvent(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		l.seq, l.prev = last.Seq, last.Hash
+	}
+
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Log completes the event with its sequence number, time and hashes and writes it.
+func (l *Logger) Log(e Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = l.seq + 1
+	e.Time = l.now().UTC()
+	e.PrevHash = l.prev
+
+	hash, err := e.computeHash(l.cfg.Key)
+	if err != nil {
+		return fmt.Errorf("failed to hash audit event: %w", err)
+	}
+	e.Hash = hash
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	line = append(line, '\n')
+
+	if err := l.rotateIfNeeded(int64(len(line))); err != nil {
+		return err
+	}
+
+	if _, err := l.out.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+
+	l.size += int64(len(line))
+	l.seq, l.prev = e.Seq, e.Hash
+
+	return nil
+}
+
+// Close closes the audit file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Close()
+}
+
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+
+	l.file, l.out, l.size = f, f, info.Size()
+
+	return nil
+}
+
+// rotateIfNeeded moves the current file to path.1, shifting older backups, once writing n more
+// bytes would exceed the maximum size. The chain continues in the new file.
+func (l *Logger) rotateIfNeeded(n int64) error {
+	if l.file == nil || l.cfg.MaxSize <= 0 || l.size == 0 || l.size+n <= l.cfg.MaxSize {
+		return nil
+	}
+
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+
+	if l.cfg.MaxBackups <= 0 {
+		if err := os.Remove(l.cfg.Path); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+
+		return l.open()
+	}
+
+	_ = os.Remove(backupPath(l.cfg.Path, l.cfg.MaxBackups))
+	for i := l.cfg.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(l.cfg.Path, i), backupPath(l.cfg.Path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+
+	if err := os.Rename(l.cfg.Path, backupPath(l.cfg.Path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	return l.open()
+}
+
+func backupPath(path string, i int) string {
+	return path + "." + strconv.Itoa(i)
+}
+
+// lastEvent returns the last event of an audit file, or nil if the file doesn't exist or is empty.
+func lastEvent(path string) (*Event, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var last []byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) > 0 {
+			last = append(last[:0], scanner.Bytes()...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	if last == nil {
+		return nil, nil
+	}
+
+	e := &Event{}
+	if err := json.Unmarshal(last, e); err != nil {
+		return nil, fmt.Errorf("failed to decode last audit event: %w", err)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,138 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger(t *testing.T, cfg Config) *Logger {
+	t.Helper()
+
+	l, err := New(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	return l
+}
+
+func TestLoggerChainsEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := newTestLogger(t, Config{Path: path})
+
+	require.NoError(t, l.Log(Event{Operation: "secret.get", SecretIDs: []string{"id-1"}, Outcome: OutcomeSuccess}))
+	require.NoError(t, l.Log(Event{Operation: "secret.update", SecretIDs: []string{"id-1"}, Outcome: OutcomeFailure}))
+
+	res, err := VerifyFiles(VerifyOptions{}, path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Events)
+	assert.Equal(t, uint64(1), res.FirstSeq)
+	assert.Empty(t, res.FirstPrevHash)
+	assert.Equal(t, uint64(2), res.LastSeq)
+}
+
+func TestLoggerContinuesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := New(Config{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, l.Log(Event{Operation: "secret.get"}))
+	require.NoError(t, l.Close())
+
+	l = newTestLogger(t, Config{Path: path})
+	require.NoError(t, l.Log(Event{Operation: "secret.get"}))
+
+	res, err := VerifyFiles(VerifyOptions{}, path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Events)
+	assert.Equal(t, uint64(2), res.LastSeq)
+}
+
+func TestLoggerRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	// Small enough that every event ends up in its own file.
+	l := newTestLogger(t, Config{Path: path, MaxSize: 10, MaxBackups: 2})
+
+	for range 4 {
+		require.NoError(t, l.Log(Event{Operation: "secret.get"}))
+	}
+
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	// The first event has been rotated away.
+	_, err := VerifyFiles(VerifyOptions{}, path+".2", path+".1", path)
+	require.ErrorIs(t, err, ErrTampered)
+
+	res, err := VerifyFiles(VerifyOptions{AllowRotatedHead: true}, path+".2", path+".1", path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Events)
+	assert.Equal(t, uint64(2), res.FirstSeq)
+	assert.Equal(t, uint64(4), res.LastSeq)
+
+	_, err = VerifyFiles(VerifyOptions{AllowRotatedHead: true}, path+".1", path+".2", path)
+	require.ErrorIs(t, err, ErrTampered)
+}
+
+func TestLoggerWithKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := newTestLogger(t, Config{Path: path, Key: "secret"})
+
+	require.NoError(t, l.Log(Event{Operation: "secret.get"}))
+	require.NoError(t, l.Log(Event{Operation: "secret.get"}))
+
+	res, err := VerifyFiles(VerifyOptions{Key: "secret"}, path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Events)
+
+	_, err = VerifyFiles(VerifyOptions{}, path)
+	require.ErrorIs(t, err, ErrTampered)
+	_, err = VerifyFiles(VerifyOptions{Key: "other"}, path)
+	require.ErrorIs(t, err, ErrTampered)
+}
+
+func TestReadKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(path, []byte("secret\n"), 0o600))
+
+	key, err := ReadKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", key)
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o600))
+	_, err = ReadKey(empty)
+	require.Error(t, err)
+
+	_, err = ReadKey(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestNewWithUnreadableLastEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+
+	_, err := New(Config{Path: path})
+	require.Error(t, err)
+}
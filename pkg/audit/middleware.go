@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+type contextKey string
+
+var contextEventKey contextKey = "audit-event"
+
+// Middleware records an event for every request once it is done. Handlers describe what the
+// request did using Annotate. Requests that aren't annotated are recorded with their method and
+// route as operation. A nil logger disables auditing.
+func Middleware(l *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			e := &Event{
+				Caller:    bitwarden.Fingerprint(r.Header.Get(bitwarden.WardenHeaderAccessToken)),
+				ClientIP:  clientIP(r),
+				RequestID: middleware.GetReqID(r.Context()),
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(WithEvent(r.Context(), e)))
+
+			if e.Operation == "" {
+				e.Operation = r.Method + " " + r.URL.Path
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					e.Operation = r.Method + " " + rctx.RoutePattern()
+				}
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			l.Record(e, status)
+		})
+	}
+}
+
+// WithEvent returns a context through which handlers annotate the event, see Annotate. It is used
+// by transports other than http, which record the event themselves once the call is done.
+func WithEvent(ctx context.Context, e *Event) context.Context {
+	return context.WithValue(ctx, contextEventKey, e)
+}
+
+// Record completes the event with the http status of the request, or its equivalent, and logs it.
+// Failures are logged and don't fail the request. It does nothing on a nil logger.
+func (l *Logger) Record(e *Event, status int) {
+	if l == nil {
+		return
+	}
+
+	e.Status = status
+	e.Outcome = outcome(status)
+
+	if err := l.Log(*e); err != nil {
+		slog.Error("failed to write audit event", "error", err, "operation", e.Operation)
+	}
+}
+
+// Annotate updates the event of the request the context belongs to. It does nothing if the
+// request isn't audited.
+func Annotate(ctx context.Context, update func(e *Event)) {
+	if e, ok := ctx.Value(contextEventKey).(*Event); ok {
+		update(e)
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func outcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= http.StatusBadRequest:
+		return OutcomeFailure
+	}
+
+	return OutcomeSuccess
+}
@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		status   int
+		annotate bool
+		expected Event
+	}{
+		{
+			name:     "annotated request",
+			token:    "token",
+			status:   http.StatusOK,
+			annotate: true,
+			expected: Event{Operation: "secret.get", SecretIDs: []string{"id-1"}, Caller: bitwarden.Fingerprint("token"), ClientIP: "192.0.2.1", Outcome: OutcomeSuccess, Status: http.StatusOK},
+		},
+		{
+			name:     "denied request",
+			status:   http.StatusUnauthorized,
+			expected: Event{Operation: "GET /secret", ClientIP: "192.0.2.1", Outcome: OutcomeDenied, Status: http.StatusUnauthorized},
+		},
+		{
+			name:     "failed request",
+			token:    "token",
+			status:   http.StatusBadGateway,
+			annotate: true,
+			expected: Event{Operation: "secret.get", SecretIDs: []string{"id-1"}, Caller: bitwarden.Fingerprint("token"), ClientIP: "192.0.2.1", Outcome: OutcomeFailure, Status: http.StatusBadGateway},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			l := newTestLogger(t, Config{Path: path})
+
+			r := chi.NewRouter()
+			r.Use(Middleware(l))
+			r.Get("/secret", func(w http.ResponseWriter, r *http.Request) {
+				if tt.annotate {
+					Annotate(r.Context(), func(e *Event) {
+						e.Operation, e.SecretIDs = "secret.get", []string{"id-1"}
+					})
+				}
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/secret", http.NoBody)
+			if tt.token != "" {
+				req.Header.Set(bitwarden.WardenHeaderAccessToken, tt.token)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			if tt.token != "" {
+				assert.NotContains(t, string(content), `"`+tt.token+`"`)
+			}
+
+			var e Event
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(content))), &e))
+			tt.expected.Seq, tt.expected.Time, tt.expected.Hash = e.Seq, e.Time, e.Hash
+			assert.Equal(t, tt.expected, e)
+		})
+	}
+}
+
+func TestMiddlewareWithoutLogger(t *testing.T) {
+	called := false
+	handler := Middleware(nil)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		called = true
+		// Annotating requests that aren't audited is a no-op.
+		Annotate(r.Context(), func(e *Event) {
+			t.Fatal("request should not be audited")
+		})
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/secret", http.NoBody))
+	assert.True(t, called)
+}
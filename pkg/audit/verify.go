@@ -0,0 +1,165 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxLineSize limits the size of a single event when reading audit logs.
+const maxLineSize = 1024 * 1024
+
+// ErrTampered is returned when an audit log doesn't form an intact chain.
+var ErrTampered = errors.New("audit log has been tampered with")
+
+// Result summarizes a verified audit log.
+type Result struct {
+	// Events is the number of verified events.
+	Events int
+	// FirstSeq and FirstPrevHash describe where the log starts. A log continuing a rotated file
+	// starts with the last hash of that file.
+	FirstSeq      uint64
+	FirstPrevHash string
+	// LastSeq and LastHash describe where the log ends.
+	LastSeq  uint64
+	LastHash string
+}
+
+// VerifyOptions configures how audit logs are verified.
+type VerifyOptions struct {
+	// Key is the key the log was written with, empty for unkeyed logs.
+	Key string
+	// AllowRotatedHead accepts logs that don't start with the first event, like the oldest
+	// rotated file left once older ones have been removed. Events before it can't be verified.
+	AllowRotatedHead bool
+}
+
+// Verify checks that the log starts with the first event, that every event carries its own hash
+// and the hash of the event before it, and that sequence numbers have no gaps.
+func Verify(r io.Reader, opts VerifyOptions) (Result, error) {
+	res, err := verifyChain(r, opts.Key)
+	if err != nil {
+		return res, err
+	}
+
+	return res, checkHead(res, opts)
+}
+
+// checkHead rejects logs not starting with the first event, which means leading events have been
+// removed, unless rotated heads are allowed.
+func checkHead(res Result, opts VerifyOptions) error {
+	if res.Events == 0 || opts.AllowRotatedHead || (res.FirstSeq == 1 && res.FirstPrevHash == "") {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the log starts at sequence %d instead of 1, events before it are missing", ErrTampered, res.FirstSeq)
+}
+
+// verifyChain verifies the hashes and sequence numbers of the events, wherever the log starts.
+func verifyChain(r io.Reader, key string) (Result, error) {
+	var res Result
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return res, fmt.Errorf("%w: line %d is not a valid event: %w", ErrTampered, line, err)
+		}
+
+		hash, err := e.computeHash(key)
+		if err != nil {
+			return res, fmt.Errorf("line %d: %w", line, err)
+		}
+		if hash != e.Hash {
+			return res, fmt.Errorf("%w: line %d has been modified", ErrTampered, line)
+		}
+
+		if res.Events == 0 {
+			res.FirstSeq, res.FirstPrevHash = e.Seq, e.PrevHash
+		} else {
+			if e.PrevHash != res.LastHash {
+				return res, fmt.Errorf("%w: line %d doesn't follow the event before it", ErrTampered, line)
+			}
+			if e.Seq != res.LastSeq+1 {
+				return res, fmt.Errorf("%w: line %d has sequence %d, expected %d", ErrTampered, line, e.Seq, res.LastSeq+1)
+			}
+		}
+
+		res.Events++
+		res.LastSeq, res.LastHash = e.Seq, e.Hash
+	}
+
+	if err := scanner.Err(); err != nil {
+		return res, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return res, nil
+}
+
+// VerifyFiles verifies audit files that continue each other, oldest first, like rotated backups
+// followed by the current file. The first file has to start the log, unless rotated heads are
+// allowed.
+func VerifyFiles(opts VerifyOptions, paths ...string) (Result, error) {
+	var (
+		total    Result
+		previous string
+	)
+	for _, path := range paths {
+		res, err := verifyFile(path, opts.Key)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if res.Events == 0 {
+			continue
+		}
+
+		if total.Events == 0 {
+			total.FirstSeq, total.FirstPrevHash = res.FirstSeq, res.FirstPrevHash
+		} else if res.FirstPrevHash != total.LastHash || res.FirstSeq != total.LastSeq+1 {
+			return total, fmt.Errorf("%w: %s doesn't continue %s", ErrTampered, path, previous)
+		}
+		previous = path
+
+		total.Events += res.Events
+		total.LastSeq, total.LastHash = res.LastSeq, res.LastHash
+	}
+
+	return total, checkHead(total, opts)
+}
+
+func verifyFile(path, key string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return verifyChain(f, key)
+}
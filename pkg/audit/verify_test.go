@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLog writes three chained events and returns their lines.
+func writeLog(t *testing.T, key string) []string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := newTestLogger(t, Config{Path: path, Key: key})
+	for _, id := range []string{"id-1", "id-2", "id-3"} {
+		require.NoError(t, l.Log(Event{Operation: "secret.get", SecretIDs: []string{id}, Outcome: OutcomeSuccess}))
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// rewrite modifies the second event and recomputes the unkeyed chain, like anyone able to write
+// the log can.
+func rewrite(t *testing.T, lines []string) []string {
+	t.Helper()
+
+	prev := ""
+	for i, line := range lines {
+		var e Event
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		if i == 1 {
+			e.SecretIDs = []string{"id-9"}
+		}
+
+		e.PrevHash = prev
+		hash, err := e.computeHash("")
+		require.NoError(t, err)
+		e.Hash, prev = hash, hash
+
+		content, err := json.Marshal(e)
+		require.NoError(t, err)
+		lines[i] = string(content)
+	}
+
+	return lines
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		tamper  func(t *testing.T, lines []string) []string
+		wantErr bool
+	}{
+		{
+			name:   "intact",
+			tamper: func(_ *testing.T, lines []string) []string { return lines },
+		},
+		{
+			name:   "intact with key",
+			key:    "secret",
+			tamper: func(_ *testing.T, lines []string) []string { return lines },
+		},
+		{
+			// Without a key, a rewritten chain can't be told apart from the original.
+			name:   "rewritten chain",
+			tamper: rewrite,
+		},
+		{
+			name:    "rewritten chain with key",
+			key:     "secret",
+			tamper:  rewrite,
+			wantErr: true,
+		},
+		{
+			name: "modified event",
+			tamper: func(_ *testing.T, lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "id-2", "id-9", 1)
+
+				return lines
+			},
+			wantErr: true,
+		},
+		{
+			name: "removed event",
+			tamper: func(_ *testing.T, lines []string) []string {
+				return slices.Delete(lines, 1, 2)
+			},
+			wantErr: true,
+		},
+		{
+			name: "removed first event",
+			tamper: func(_ *testing.T, lines []string) []string {
+				return lines[1:]
+			},
+			wantErr: true,
+		},
+		{
+			name: "reordered events",
+			tamper: func(_ *testing.T, lines []string) []string {
+				lines[0], lines[1] = lines[1], lines[0]
+
+				return lines
+			},
+			wantErr: true,
+		},
+		{
+			name: "garbage",
+			tamper: func(_ *testing.T, lines []string) []string {
+				return append(lines, "not json")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := tt.tamper(t, writeLog(t, tt.key))
+
+			res, err := Verify(strings.NewReader(strings.Join(lines, "\n")), VerifyOptions{Key: tt.key})
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrTampered)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 3, res.Events)
+		})
+	}
+}
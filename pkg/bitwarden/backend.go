@@ -0,0 +1,47 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"context"
+
+	"github.com/bitwarden/sdk-go/v2"
+)
+
+// Backend provides the authenticated clients the Warden hands to requests. The clients of a
+// backend are closed once the requests using them are done.
+type Backend interface {
+	Login(ctx context.Context, req *LoginRequest) (sdk.BitwardenClientInterface, error)
+}
+
+// BackendFunc adapts a function to a Backend.
+type BackendFunc func(ctx context.Context, req *LoginRequest) (sdk.BitwardenClientInterface, error)
+
+// Login calls f.
+func (f BackendFunc) Login(ctx context.Context, req *LoginRequest) (sdk.BitwardenClientInterface, error) {
+	return f(ctx, req)
+}
+
+// SDKBackend logs in to Bitwarden using the SDK, see Login.
+var SDKBackend Backend = BackendFunc(Login)
+
+// backendOrDefault returns the backend, or the SDKBackend if it is nil.
+func backendOrDefault(b Backend) Backend {
+	if b == nil {
+		return SDKBackend
+	}
+
+	return b
+}
@@ -16,16 +16,28 @@ package bitwarden
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/bitwarden/sdk-go/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/metrics"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/tracing"
 )
 
 type contextKey string
 
-var ContextClientKey contextKey = "warden-client"
+var (
+	ContextClientKey   contextKey = "warden-client"
+	ContextIdentityKey contextKey = "warden-identity"
+	contextLeaseKey    contextKey = "warden-lease"
+)
 
 // Default Settings.
 const (
@@ -72,11 +84,20 @@ var newBitwardenClientFn = sdk.NewBitwardenClient
 // Login creates a session for further Bitwarden requests.
 // Note: I don't like returning the interface, but that's what
 // the client returns.
-func Login(req *LoginRequest) (sdk.BitwardenClientInterface, error) {
+func Login(ctx context.Context, req *LoginRequest) (_ sdk.BitwardenClientInterface, err error) {
+	_, span := tracing.Tracer().Start(ctx, "bitwarden.Login")
+	start := time.Now()
+	defer func() {
+		metrics.ObserveLogin(start, err)
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	// Configuring the URLS is optional, set them to nil to use the default values
 	apiURL := setOrDefault(req.APIURL, defaultAPIURL)
 	identityURL := setOrDefault(req.IdentityURL, defaultIdentityURL)
 	statePath := setOrDefault(req.StatePath, defaultStatePath)
+	span.SetAttributes(attribute.String("bitwarden.api_url", apiURL), attribute.String("bitwarden.identity_url", identityURL))
 
 	// Client is closed in the calling handlers.
 	slog.Debug("constructed client with api and identity url", "api", apiURL, "identityUrl", identityURL, "statePath", statePath)
@@ -84,6 +105,7 @@ func Login(req *LoginRequest) (sdk.BitwardenClientInterface, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
+	metrics.ClientCreated()
 
 	if err := bitwardenClient.AccessTokenLogin(req.AccessToken, &statePath); err != nil {
 		return nil, fmt.Errorf("bitwarden login: %w", err)
@@ -92,6 +114,19 @@ func Login(req *LoginRequest) (sdk.BitwardenClientInterface, error) {
 	return bitwardenClient, nil
 }
 
+// WardenOptions configures the Warden middleware.
+type WardenOptions struct {
+	// Backend provides the authenticated clients. If nil, clients log in to Bitwarden using the SDK.
+	Backend Backend
+	// Sessions is used to reuse authenticated clients between requests. If nil, every
+	// request logs in and closes its client once it's done.
+	Sessions *SessionPool
+	// URLPolicy restricts the API and identity URLs callers can use. If nil, any URL is accepted.
+	URLPolicy *URLPolicy
+	// StatePolicy confines state files to a directory. If nil, state paths are used as provided.
+	StatePolicy *StatePolicy
+}
+
 // Warden is a middleware to use with the bitwarden API.
 // Header used by the Warden:
 // Warden-Access-Token: <token>
@@ -101,33 +136,170 @@ func Login(req *LoginRequest) (sdk.BitwardenClientInterface, error) {
 // Put the client into the context and so if a context contains our client
 // we know that calls are authenticated.
 func Warden(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get(WardenHeaderAccessToken)
-		if token == "" {
-			http.Error(w, "Missing Warden access token", http.StatusUnauthorized)
+	return NewWarden(WardenOptions{})(next)
+}
 
-			return
-		}
+// NewWarden returns a Warden middleware configured with the given options.
+func NewWarden(opts WardenOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, done, err := opts.Authenticate(r.Context(), &LoginRequest{
+				RequestBase: &RequestBase{
+					APIURL:      r.Header.Get(WardenHeaderAPIURL),
+					IdentityURL: r.Header.Get(WardenHeaderIdentityURL),
+				},
+				AccessToken: r.Header.Get(WardenHeaderAccessToken),
+				StatePath:   r.Header.Get(WardenHeaderStatePath),
+			})
+			if err != nil {
+				apierror.Write(w, r, err)
 
-		loginRequest := &LoginRequest{
-			RequestBase: &RequestBase{
-				APIURL:      r.Header.Get(WardenHeaderAPIURL),
-				IdentityURL: r.Header.Get(WardenHeaderIdentityURL),
-			},
-			AccessToken: token,
-			StatePath:   r.Header.Get(WardenHeaderStatePath),
-		}
+				return
+			}
+			defer done()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authenticate enforces the policies on the login request and returns a context carrying an
+// authenticated client, see WithClient. The returned function must be called once the client is
+// no longer needed. Errors carry the status to report to the caller.
+func (o WardenOptions) Authenticate(ctx context.Context, loginRequest *LoginRequest) (context.Context, func(), error) {
+	if loginRequest.AccessToken == "" {
+		return nil, nil, apierror.Errorf(http.StatusUnauthorized, apierror.CodeUnauthorized, "Missing Warden access token")
+	}
+
+	// The span ends with the login, later calls are parented to the request instead.
+	spanCtx, span := tracing.Tracer().Start(ctx, "bitwarden.Warden")
+	client, release, err := o.authenticate(spanCtx, loginRequest)
+	tracing.RecordError(span, err)
+	span.End()
+	if err != nil {
+		return nil, nil, err
+	}
 
-		// Make sure every request gets its own client that it will close after it's done.
-		client, err := Login(loginRequest)
-		if err != nil {
-			http.Error(w, "failed to login to bitwarden using access token: "+err.Error(), http.StatusBadRequest)
+	ctx, done := WithClient(ctx, instrument(ctx, client), Identity(loginRequest), release)
 
-			return
+	return ctx, done, nil
+}
+
+func (o WardenOptions) authenticate(ctx context.Context, loginRequest *LoginRequest) (sdk.BitwardenClientInterface, func(), error) {
+	if loginRequest.RequestBase == nil {
+		loginRequest.RequestBase = &RequestBase{}
+	}
+
+	if err := o.URLPolicy.Apply(loginRequest.RequestBase); err != nil {
+		return nil, nil, apierror.New(http.StatusForbidden, apierror.CodeForbidden, fmt.Errorf("rejected bitwarden url: %w", err))
+	}
+
+	if err := o.StatePolicy.Resolve(loginRequest); err != nil {
+		if errors.Is(err, ErrInvalidStatePath) {
+			return nil, nil, apierror.New(http.StatusBadRequest, apierror.CodeInvalidRequest, fmt.Errorf("rejected state path: %w", err))
 		}
-		defer client.Close()
 
-		ctx := context.WithValue(r.Context(), ContextClientKey, client)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+		return nil, nil, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, fmt.Errorf("failed to resolve state path: %w", err))
+	}
+
+	client, release, err := o.acquire(ctx, loginRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to login to bitwarden using access token: %w", err)
+	}
+
+	return client, release, nil
+}
+
+// Identity returns a fingerprint of the credentials used by the login request. Requests with the
+// same identity are able to see the same secrets. The access token can't be recovered from it.
+func Identity(req *LoginRequest) string {
+	var apiURL, identityURL string
+	if req.RequestBase != nil {
+		apiURL, identityURL = req.APIURL, req.IdentityURL
+	}
+
+	return Fingerprint(req.AccessToken, setOrDefault(apiURL, defaultAPIURL), setOrDefault(identityURL, defaultIdentityURL))
+}
+
+// WithClient returns a context carrying an authenticated client and the identity it was
+// authenticated with. release is called once the returned function has been called and every
+// user that retained the client is finished with it.
+func WithClient(ctx context.Context, client sdk.BitwardenClientInterface, identity string, release func()) (context.Context, func()) {
+	l := &lease{refs: 1, release: release}
+
+	ctx = context.WithValue(ctx, ContextClientKey, client)
+	ctx = context.WithValue(ctx, ContextIdentityKey, identity)
+	ctx = context.WithValue(ctx, contextLeaseKey, l)
+
+	return ctx, l.retainedDone()
+}
+
+// IdentityFromContext returns the identity of the caller authenticated by the Warden or an
+// empty string if there is none.
+func IdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(ContextIdentityKey).(string)
+
+	return identity
+}
+
+// Retain keeps the client of the request authenticated by the Warden usable after the request
+// is done, for example to finish work in the background. The returned function must be called
+// once the client is no longer needed. Returns false if the context has no client to retain.
+func Retain(ctx context.Context) (func(), bool) {
+	l, ok := ctx.Value(contextLeaseKey).(*lease)
+	if !ok {
+		return nil, false
+	}
+
+	return l.retain(), true
+}
+
+// lease counts the users of a client so it is only released once nobody needs it anymore.
+type lease struct {
+	mu      sync.Mutex
+	refs    int
+	release func()
+}
+
+func (l *lease) retain() func() {
+	l.mu.Lock()
+	l.refs++
+	l.mu.Unlock()
+
+	return l.retainedDone()
+}
+
+// retainedDone returns a function releasing one reference, no matter how often it's called.
+func (l *lease) retainedDone() func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(l.done)
+	}
+}
+
+func (l *lease) done() {
+	l.mu.Lock()
+	l.refs--
+	last := l.refs == 0
+	l.mu.Unlock()
+
+	if last {
+		l.release()
+	}
+}
+
+// acquire returns an authenticated client and a function to call once the request is done with it.
+func (o WardenOptions) acquire(ctx context.Context, req *LoginRequest) (sdk.BitwardenClientInterface, func(), error) {
+	if o.Sessions != nil {
+		return o.Sessions.Acquire(ctx, req)
+	}
+
+	// Make sure every request gets its own client that it will close after it's done.
+	client, err := backendOrDefault(o.Backend).Login(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, client.Close, nil
 }
@@ -16,6 +16,8 @@ package bitwarden
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -25,6 +27,10 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // This is a valid test token that has been generated and then revoked. The important
@@ -104,3 +110,157 @@ func TestWardenWithToken(t *testing.T) {
 	assert.Equal(t, "test", string(content))
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
+
+func TestWithClientRetain(t *testing.T) {
+	var released int
+	ctx, done := WithClient(context.Background(), &testClient{}, "identity", func() { released++ })
+
+	assert.Equal(t, "identity", IdentityFromContext(ctx))
+	assert.NotNil(t, ctx.Value(ContextClientKey))
+
+	release, ok := Retain(ctx)
+	require.True(t, ok)
+
+	done()
+	done()
+	assert.Equal(t, 0, released, "client is still retained")
+
+	release()
+	assert.Equal(t, 1, released)
+
+	_, ok = Retain(context.Background())
+	assert.False(t, ok)
+}
+
+func TestIdentity(t *testing.T) {
+	base := &LoginRequest{RequestBase: &RequestBase{}, AccessToken: testToken}
+	other := &LoginRequest{RequestBase: &RequestBase{APIURL: "https://api.example.com"}, AccessToken: testToken}
+	withStatePath := &LoginRequest{RequestBase: &RequestBase{}, AccessToken: testToken, StatePath: "state"}
+
+	assert.NotEqual(t, Identity(base), Identity(other))
+	assert.Equal(t, Identity(base), Identity(withStatePath))
+	assert.NotContains(t, Identity(base), testToken)
+}
+
+func TestWardenWithoutToken(t *testing.T) {
+	handler := Warden(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"code": "unauthorized", "message": "Missing Warden access token", "retryable": false}`, w.Body.String())
+}
+
+func TestWardenLoginFailure(t *testing.T) {
+	prevBitwardenClient := newBitwardenClientFn
+	newBitwardenClientFn = func(_, _ *string) (sdk.BitwardenClientInterface, error) {
+		return &failingLoginClient{}, nil
+	}
+	defer func() {
+		newBitwardenClientFn = prevBitwardenClient
+	}()
+
+	handler := Warden(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(WardenHeaderAccessToken, testToken)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"unauthorized"`)
+}
+
+type failingLoginClient struct {
+	testClient
+}
+
+func (c *failingLoginClient) AccessTokenLogin(_ string, _ *string) error {
+	return errors.New("API error: invalid_client")
+}
+
+func TestInstrumentKeepsNilInterfaces(t *testing.T) {
+	client := instrument(context.Background(), &testClient{})
+
+	assert.Nil(t, client.Secrets())
+	assert.Nil(t, client.Generators())
+	assert.NotNil(t, client.Projects())
+}
+
+func TestInstrumentRecordsSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prevProvider)
+
+	client := instrument(context.Background(), &secretsClient{secrets: &valueSecrets{}})
+	_, err := client.Secrets().GetByIDS([]string{"id-1", "id-2"})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "bitwarden.secrets.GetByIDS", spans[0].Name())
+	assert.Equal(t, []attribute.KeyValue{attrSecretIDs.StringSlice([]string{"id-1", "id-2"})}, spans[0].Attributes())
+}
+
+func TestAuthenticateParentsCallsToTheRequest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prevProvider)
+
+	prev := newBitwardenClientFn
+	newBitwardenClientFn = func(_, _ *string) (sdk.BitwardenClientInterface, error) {
+		return &secretsClient{secrets: &valueSecrets{}}, nil
+	}
+	defer func() {
+		newBitwardenClientFn = prev
+	}()
+
+	ctx, request := otel.Tracer("test").Start(context.Background(), "request")
+	ctx, done, err := WardenOptions{}.Authenticate(ctx, &LoginRequest{AccessToken: testToken})
+	require.NoError(t, err)
+	defer done()
+
+	client, ok := ctx.Value(ContextClientKey).(sdk.BitwardenClientInterface)
+	require.True(t, ok)
+	_, err = client.Secrets().GetByIDS([]string{"id-1"})
+	require.NoError(t, err)
+	request.End()
+
+	parents := map[string]string{}
+	for _, span := range recorder.Ended() {
+		parents[span.Name()] = span.Parent().SpanID().String()
+	}
+	requestID := request.SpanContext().SpanID().String()
+	assert.Equal(t, requestID, parents["bitwarden.Warden"])
+	assert.Equal(t, requestID, parents["bitwarden.secrets.GetByIDS"])
+}
+
+type secretsClient struct {
+	testClient
+
+	secrets sdk.SecretsInterface
+}
+
+func (c *secretsClient) Secrets() sdk.SecretsInterface {
+	return c.secrets
+}
+
+type valueSecrets struct {
+	sdk.SecretsInterface
+}
+
+func (s *valueSecrets) GetByIDS(ids []string) (*sdk.SecretsResponse, error) {
+	resp := &sdk.SecretsResponse{}
+	for _, id := range ids {
+		resp.Data = append(resp.Data, sdk.SecretResponse{ID: id, Value: "value-" + id})
+	}
+
+	return resp, nil
+}
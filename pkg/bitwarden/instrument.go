@@ -0,0 +1,195 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/metrics"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/tracing"
+)
+
+// Resources used to label SDK call metrics.
+const (
+	resourceSecrets    = "secrets"
+	resourceProjects   = "projects"
+	resourceGenerators = "generators"
+)
+
+// Attributes recorded on SDK call spans. Only identifiers are recorded, never secret values.
+const (
+	attrSecretIDs      = attribute.Key("bitwarden.secret.ids")
+	attrProjectIDs     = attribute.Key("bitwarden.project.ids")
+	attrOrganizationID = attribute.Key("bitwarden.organization.id")
+)
+
+// instrument wraps a client so every SDK call made through it is measured and traced as a
+// child of the span in ctx.
+func instrument(ctx context.Context, client sdk.BitwardenClientInterface) sdk.BitwardenClientInterface {
+	return &instrumentedClient{BitwardenClientInterface: client, ctx: ctx}
+}
+
+// observe runs an SDK call and records its latency, result and a span with the given attributes.
+func observe[T any](ctx context.Context, resource, operation string, attrs []attribute.KeyValue, call func() (T, error)) (T, error) {
+	_, span := tracing.Tracer().Start(ctx, "bitwarden."+resource+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := call()
+	metrics.ObserveSDKCall(resource, operation, start, err)
+	tracing.RecordError(span, err)
+
+	return resp, err
+}
+
+type instrumentedClient struct {
+	sdk.BitwardenClientInterface
+
+	ctx context.Context
+}
+
+func (c *instrumentedClient) Secrets() sdk.SecretsInterface {
+	secrets := c.BitwardenClientInterface.Secrets()
+	if secrets == nil {
+		return nil
+	}
+
+	return &instrumentedSecrets{ctx: c.ctx, secrets: secrets}
+}
+
+func (c *instrumentedClient) Projects() sdk.ProjectsInterface {
+	projects := c.BitwardenClientInterface.Projects()
+	if projects == nil {
+		return nil
+	}
+
+	return &instrumentedProjects{ctx: c.ctx, projects: projects}
+}
+
+func (c *instrumentedClient) Generators() sdk.GeneratorsInterface {
+	generators := c.BitwardenClientInterface.Generators()
+	if generators == nil {
+		return nil
+	}
+
+	return &instrumentedGenerators{ctx: c.ctx, generators: generators}
+}
+
+type instrumentedSecrets struct {
+	ctx     context.Context
+	secrets sdk.SecretsInterface
+}
+
+var _ sdk.SecretsInterface = &instrumentedSecrets{}
+
+func (s *instrumentedSecrets) Create(key, value, note, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	return observe(s.ctx, resourceSecrets, "Create", []attribute.KeyValue{attrOrganizationID.String(organizationID), attrProjectIDs.StringSlice(projectIDs)}, func() (*sdk.SecretResponse, error) {
+		return s.secrets.Create(key, value, note, organizationID, projectIDs)
+	})
+}
+
+func (s *instrumentedSecrets) List(organizationID string) (*sdk.SecretIdentifiersResponse, error) {
+	return observe(s.ctx, resourceSecrets, "List", []attribute.KeyValue{attrOrganizationID.String(organizationID)}, func() (*sdk.SecretIdentifiersResponse, error) {
+		return s.secrets.List(organizationID)
+	})
+}
+
+func (s *instrumentedSecrets) Get(secretID string) (*sdk.SecretResponse, error) {
+	return observe(s.ctx, resourceSecrets, "Get", []attribute.KeyValue{attrSecretIDs.StringSlice([]string{secretID})}, func() (*sdk.SecretResponse, error) {
+		return s.secrets.Get(secretID)
+	})
+}
+
+func (s *instrumentedSecrets) GetByIDS(secretIDs []string) (*sdk.SecretsResponse, error) {
+	return observe(s.ctx, resourceSecrets, "GetByIDS", []attribute.KeyValue{attrSecretIDs.StringSlice(secretIDs)}, func() (*sdk.SecretsResponse, error) {
+		return s.secrets.GetByIDS(secretIDs)
+	})
+}
+
+func (s *instrumentedSecrets) Update(secretID, key, value, note, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	return observe(s.ctx, resourceSecrets, "Update", []attribute.KeyValue{attrSecretIDs.StringSlice([]string{secretID}), attrOrganizationID.String(organizationID), attrProjectIDs.StringSlice(projectIDs)}, func() (*sdk.SecretResponse, error) {
+		return s.secrets.Update(secretID, key, value, note, organizationID, projectIDs)
+	})
+}
+
+func (s *instrumentedSecrets) Delete(secretIDs []string) (*sdk.SecretsDeleteResponse, error) {
+	return observe(s.ctx, resourceSecrets, "Delete", []attribute.KeyValue{attrSecretIDs.StringSlice(secretIDs)}, func() (*sdk.SecretsDeleteResponse, error) {
+		return s.secrets.Delete(secretIDs)
+	})
+}
+
+func (s *instrumentedSecrets) Sync(organizationID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	return observe(s.ctx, resourceSecrets, "Sync", []attribute.KeyValue{attrOrganizationID.String(organizationID)}, func() (*sdk.SecretsSyncResponse, error) {
+		return s.secrets.Sync(organizationID, lastSyncedDate)
+	})
+}
+
+type instrumentedProjects struct {
+	ctx      context.Context
+	projects sdk.ProjectsInterface
+}
+
+var _ sdk.ProjectsInterface = &instrumentedProjects{}
+
+func (p *instrumentedProjects) Create(organizationID, name string) (*sdk.ProjectResponse, error) {
+	return observe(p.ctx, resourceProjects, "Create", []attribute.KeyValue{attrOrganizationID.String(organizationID)}, func() (*sdk.ProjectResponse, error) {
+		return p.projects.Create(organizationID, name)
+	})
+}
+
+func (p *instrumentedProjects) List(organizationID string) (*sdk.ProjectsResponse, error) {
+	return observe(p.ctx, resourceProjects, "List", []attribute.KeyValue{attrOrganizationID.String(organizationID)}, func() (*sdk.ProjectsResponse, error) {
+		return p.projects.List(organizationID)
+	})
+}
+
+func (p *instrumentedProjects) Get(projectID string) (*sdk.ProjectResponse, error) {
+	return observe(p.ctx, resourceProjects, "Get", []attribute.KeyValue{attrProjectIDs.StringSlice([]string{projectID})}, func() (*sdk.ProjectResponse, error) {
+		return p.projects.Get(projectID)
+	})
+}
+
+func (p *instrumentedProjects) Update(projectID, organizationID, name string) (*sdk.ProjectResponse, error) {
+	return observe(p.ctx, resourceProjects, "Update", []attribute.KeyValue{attrProjectIDs.StringSlice([]string{projectID}), attrOrganizationID.String(organizationID)}, func() (*sdk.ProjectResponse, error) {
+		return p.projects.Update(projectID, organizationID, name)
+	})
+}
+
+func (p *instrumentedProjects) Delete(projectIDs []string) (*sdk.ProjectsDeleteResponse, error) {
+	return observe(p.ctx, resourceProjects, "Delete", []attribute.KeyValue{attrProjectIDs.StringSlice(projectIDs)}, func() (*sdk.ProjectsDeleteResponse, error) {
+		return p.projects.Delete(projectIDs)
+	})
+}
+
+type instrumentedGenerators struct {
+	ctx        context.Context
+	generators sdk.GeneratorsInterface
+}
+
+var _ sdk.GeneratorsInterface = &instrumentedGenerators{}
+
+func (g *instrumentedGenerators) GeneratePassword(request sdk.PasswordGeneratorRequest) (*string, error) {
+	return observe(g.ctx, resourceGenerators, "GeneratePassword", nil, func() (*string, error) {
+		return g.generators.GeneratePassword(request)
+	})
+}
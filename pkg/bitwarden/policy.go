@@ -0,0 +1,120 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrURLNotAllowed is returned when a caller provided URL is rejected by the URLPolicy.
+var ErrURLNotAllowed = errors.New("url not allowed")
+
+// URLPolicy restricts the API and identity URLs callers can make the server send access tokens to.
+type URLPolicy struct {
+	// AllowedHosts lists the hosts callers may use. An entry is either an exact host, optionally
+	// with a port, or a wildcard domain like `*.bitwarden.eu` matching any of its subdomains.
+	// If empty, any host is allowed.
+	AllowedHosts []string
+	// RequireHTTPS rejects URLs that don't use the https scheme.
+	RequireHTTPS bool
+	// PinURLs ignores the URLs provided by callers and always uses APIURL and IdentityURL.
+	PinURLs bool
+	// APIURL is the API URL used when the URLs are pinned. Defaults to the Bitwarden cloud.
+	APIURL string
+	// IdentityURL is the identity URL used when the URLs are pinned. Defaults to the Bitwarden cloud.
+	IdentityURL string
+}
+
+// Apply enforces the policy on the URLs of a request. Pinned URLs replace the ones provided by
+// the caller, otherwise every URL provided by the caller is checked. Empty URLs are left alone,
+// they fall back to the defaults.
+func (p *URLPolicy) Apply(req *RequestBase) error {
+	if p == nil || req == nil {
+		return nil
+	}
+
+	if p.PinURLs {
+		req.APIURL = p.APIURL
+		req.IdentityURL = p.IdentityURL
+
+		return nil
+	}
+
+	if err := p.check(req.APIURL); err != nil {
+		return fmt.Errorf("api url: %w", err)
+	}
+
+	if err := p.check(req.IdentityURL); err != nil {
+		return fmt.Errorf("identity url: %w", err)
+	}
+
+	return nil
+}
+
+func (p *URLPolicy) check(raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: %q is not an absolute url", ErrURLNotAllowed, raw)
+	}
+
+	if p.RequireHTTPS && !strings.EqualFold(u.Scheme, "https") {
+		return fmt.Errorf("%w: %q must use https", ErrURLNotAllowed, raw)
+	}
+
+	if len(p.AllowedHosts) == 0 {
+		return nil
+	}
+
+	for _, allowed := range p.AllowedHosts {
+		if hostMatches(allowed, u) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: host %q is not in the list of allowed hosts", ErrURLNotAllowed, u.Host)
+}
+
+// hostMatches reports whether the host of u matches an allowed host entry. Entries with a
+// port only match that port. Comparison ignores case and a trailing dot of fully qualified names.
+func hostMatches(allowed string, u *url.URL) bool {
+	host := normalizeHost(u.Hostname())
+	allowed = strings.ToLower(strings.TrimSpace(allowed))
+
+	if h, port, err := net.SplitHostPort(allowed); err == nil {
+		if port != u.Port() {
+			return false
+		}
+		allowed = h
+	}
+	allowed = normalizeHost(allowed)
+
+	if domain, ok := strings.CutPrefix(allowed, "*."); ok {
+		return strings.HasSuffix(host, "."+domain)
+	}
+
+	return host == allowed
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
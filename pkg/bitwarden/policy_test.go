@@ -0,0 +1,156 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLPolicyApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *URLPolicy
+		apiURL      string
+		identityURL string
+		expectError string
+	}{
+		{
+			name:   "nil policy allows anything",
+			apiURL: "http://169.254.169.254/latest",
+		},
+		{
+			name:   "empty allowlist allows any host",
+			policy: &URLPolicy{},
+			apiURL: "https://api.example.com",
+		},
+		{
+			name:        "exact host",
+			policy:      &URLPolicy{AllowedHosts: []string{"api.bitwarden.com", "identity.bitwarden.com"}},
+			apiURL:      "https://api.bitwarden.com",
+			identityURL: "https://identity.bitwarden.com",
+		},
+		{
+			name:   "exact host ignores case and trailing dot",
+			policy: &URLPolicy{AllowedHosts: []string{"api.bitwarden.com"}},
+			apiURL: "https://API.bitwarden.com./",
+		},
+		{
+			name:        "host not allowed",
+			policy:      &URLPolicy{AllowedHosts: []string{"api.bitwarden.com"}},
+			apiURL:      "https://api.bitwarden.com",
+			identityURL: "https://attacker.example.com",
+			expectError: `identity url: url not allowed: host "attacker.example.com" is not in the list of allowed hosts`,
+		},
+		{
+			name:   "wildcard domain",
+			policy: &URLPolicy{AllowedHosts: []string{"*.bitwarden.eu"}},
+			apiURL: "https://vault.bitwarden.eu/api",
+		},
+		{
+			name:        "wildcard does not match the domain itself",
+			policy:      &URLPolicy{AllowedHosts: []string{"*.bitwarden.eu"}},
+			apiURL:      "https://bitwarden.eu/api",
+			expectError: "api url: url not allowed",
+		},
+		{
+			name:        "wildcard does not match suffix without dot",
+			policy:      &URLPolicy{AllowedHosts: []string{"*.bitwarden.eu"}},
+			apiURL:      "https://evilbitwarden.eu/api",
+			expectError: "api url: url not allowed",
+		},
+		{
+			name:   "host with port",
+			policy: &URLPolicy{AllowedHosts: []string{"vault.example.com:8443"}},
+			apiURL: "https://vault.example.com:8443/api",
+		},
+		{
+			name:        "host with different port",
+			policy:      &URLPolicy{AllowedHosts: []string{"vault.example.com:8443"}},
+			apiURL:      "https://vault.example.com/api",
+			expectError: "api url: url not allowed",
+		},
+		{
+			name:        "https required",
+			policy:      &URLPolicy{RequireHTTPS: true},
+			apiURL:      "http://api.bitwarden.com",
+			expectError: `api url: url not allowed: "http://api.bitwarden.com" must use https`,
+		},
+		{
+			name:        "relative url",
+			policy:      &URLPolicy{AllowedHosts: []string{"api.bitwarden.com"}},
+			apiURL:      "/api.bitwarden.com",
+			expectError: "api url: url not allowed",
+		},
+		{
+			name:   "empty urls use the defaults",
+			policy: &URLPolicy{AllowedHosts: []string{"vault.example.com"}, RequireHTTPS: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RequestBase{APIURL: tt.apiURL, IdentityURL: tt.identityURL}
+
+			err := tt.policy.Apply(req)
+
+			if tt.expectError != "" {
+				require.ErrorIs(t, err, ErrURLNotAllowed)
+				assert.Contains(t, err.Error(), tt.expectError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.apiURL, req.APIURL)
+				assert.Equal(t, tt.identityURL, req.IdentityURL)
+			}
+		})
+	}
+}
+
+func TestURLPolicyPinURLs(t *testing.T) {
+	policy := &URLPolicy{
+		PinURLs:      true,
+		AllowedHosts: []string{"unrelated.example.com"},
+		APIURL:       "https://vault.bitwarden.eu/api",
+		IdentityURL:  "https://vault.bitwarden.eu/identity",
+	}
+	req := &RequestBase{APIURL: "http://169.254.169.254", IdentityURL: "http://169.254.169.254"}
+
+	require.NoError(t, policy.Apply(req))
+	assert.Equal(t, "https://vault.bitwarden.eu/api", req.APIURL)
+	assert.Equal(t, "https://vault.bitwarden.eu/identity", req.IdentityURL)
+}
+
+func TestWardenRejectsURLsNotAllowed(t *testing.T) {
+	clients := trackClients(t, nil)
+	policy := &URLPolicy{AllowedHosts: []string{"api.bitwarden.com"}}
+	handler := NewWarden(WardenOptions{URLPolicy: policy})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(WardenHeaderAccessToken, testToken)
+	req.Header.Set(WardenHeaderAPIURL, "http://169.254.169.254")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "is not in the list of allowed hosts")
+	assert.Empty(t, *clients, "must not log in with a rejected url")
+}
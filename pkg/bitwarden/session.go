@@ -0,0 +1,274 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// minSweepInterval limits how often the pool looks for idle sessions.
+const minSweepInterval = time.Second
+
+// session is an authenticated client shared by all requests using the same credentials.
+type session struct {
+	key      string
+	client   sdk.BitwardenClientInterface
+	refs     int
+	lastUsed time.Time
+	// evicted sessions are no longer handed out and are closed once the last user releases them.
+	evicted bool
+}
+
+// SessionPool keeps authenticated clients around so subsequent requests with the same
+// credentials don't have to log in again. Sessions are evicted after being idle for the
+// configured TTL or when the pool grows beyond its maximum size, least recently used first.
+// Sessions that are in use are never closed, eviction only prevents them from being handed
+// out again.
+type SessionPool struct {
+	mu       sync.Mutex
+	backend  Backend
+	ttl      time.Duration
+	maxSize  int
+	entries  map[string]*list.Element
+	lru      *list.List
+	now      func() time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewSessionPool creates a pool that logs in using the backend, closes sessions idle for longer
+// than ttl and keeps at most maxSize sessions. A nil backend logs in using the SDK. A maxSize of
+// zero or less means the size is not limited. The pool must be closed with Close to release the
+// remaining sessions.
+func NewSessionPool(backend Backend, ttl time.Duration, maxSize int) *SessionPool {
+	p := &SessionPool{
+		backend: backendOrDefault(backend),
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+
+	go p.sweep(max(ttl/2, minSweepInterval))
+
+	return p
+}
+
+// Acquire returns an authenticated client for the given login request, logging in only if
+// there is no cached session for these credentials yet. The returned release function must
+// be called once the caller is done with the client. The client must not be closed directly.
+func (p *SessionPool) Acquire(ctx context.Context, req *LoginRequest) (sdk.BitwardenClientInterface, func(), error) {
+	key := sessionKey(req)
+
+	p.mu.Lock()
+	expired := p.evictExpiredLocked()
+	s := p.getLocked(key)
+	p.mu.Unlock()
+
+	closeSessions(expired)
+	if s != nil {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("bitwarden.session.reused", true))
+
+		return s.client, p.releaseFunc(s), nil
+	}
+
+	client, err := p.backend.Login(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	// Another request might have logged in with the same credentials in the meantime.
+	if existing := p.getLocked(key); existing != nil {
+		p.mu.Unlock()
+		client.Close()
+
+		return existing.client, p.releaseFunc(existing), nil
+	}
+
+	s = &session{key: key, client: client, refs: 1, lastUsed: p.now()}
+	p.entries[key] = p.lru.PushFront(s)
+	closable := p.evictOverflowLocked()
+	p.mu.Unlock()
+
+	closeSessions(closable)
+
+	return s.client, p.releaseFunc(s), nil
+}
+
+// Len returns the number of sessions currently held by the pool.
+func (p *SessionPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lru.Len()
+}
+
+// Close stops the pool and closes all sessions that are not in use. Sessions in use are
+// closed as soon as they are released.
+func (p *SessionPool) Close() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+
+	p.mu.Lock()
+	var closable []*session
+	for p.lru.Len() > 0 {
+		if s := p.evictLocked(p.lru.Front()); s != nil {
+			closable = append(closable, s)
+		}
+	}
+	p.mu.Unlock()
+
+	closeSessions(closable)
+}
+
+// getLocked returns the cached session for key and marks it as used.
+func (p *SessionPool) getLocked(key string) *session {
+	e, ok := p.entries[key]
+	if !ok {
+		return nil
+	}
+
+	s := e.Value.(*session)
+	s.refs++
+	s.lastUsed = p.now()
+	p.lru.MoveToFront(e)
+
+	return s
+}
+
+func (p *SessionPool) releaseFunc(s *session) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			s.refs--
+			s.lastUsed = p.now()
+			closable := s.evicted && s.refs == 0
+			p.mu.Unlock()
+
+			if closable {
+				s.client.Close()
+			}
+		})
+	}
+}
+
+// evictLocked removes the session from the pool and returns it if it can be closed right away.
+func (p *SessionPool) evictLocked(e *list.Element) *session {
+	s := e.Value.(*session)
+	s.evicted = true
+	delete(p.entries, s.key)
+	p.lru.Remove(e)
+
+	if s.refs > 0 {
+		return nil
+	}
+
+	return s
+}
+
+func (p *SessionPool) evictOverflowLocked() []*session {
+	if p.maxSize <= 0 {
+		return nil
+	}
+
+	var closable []*session
+	for p.lru.Len() > p.maxSize {
+		if s := p.evictLocked(p.lru.Back()); s != nil {
+			closable = append(closable, s)
+		}
+	}
+
+	return closable
+}
+
+func (p *SessionPool) evictExpiredLocked() []*session {
+	now := p.now()
+
+	var closable []*session
+	for e := p.lru.Back(); e != nil; {
+		prev := e.Prev()
+		if s := e.Value.(*session); s.refs == 0 && now.Sub(s.lastUsed) > p.ttl {
+			closable = append(closable, p.evictLocked(e))
+		}
+		e = prev
+	}
+
+	return closable
+}
+
+func (p *SessionPool) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			closable := p.evictExpiredLocked()
+			p.mu.Unlock()
+
+			if len(closable) > 0 {
+				slog.Debug("closing idle bitwarden sessions", "count", len(closable))
+			}
+			closeSessions(closable)
+		}
+	}
+}
+
+func closeSessions(sessions []*session) {
+	for _, s := range sessions {
+		s.client.Close()
+	}
+}
+
+// sessionKey identifies a session by its credentials. The access token is never stored as is.
+func sessionKey(req *LoginRequest) string {
+	return Fingerprint(Identity(req), setOrDefault(req.StatePath, defaultStatePath))
+}
+
+// Fingerprint returns a hex encoded hash of the given values, identifying them without revealing
+// them, like the caller of a request by its access token. It is empty if no value is set.
+func Fingerprint(values ...string) string {
+	if !slices.ContainsFunc(values, func(v string) bool { return v != "" }) {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, v := range values {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
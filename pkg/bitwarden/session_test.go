@@ -0,0 +1,238 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closeTrackingClient struct {
+	testClient
+
+	closed atomic.Bool
+}
+
+func (c *closeTrackingClient) Close() {
+	c.closed.Store(true)
+}
+
+// trackClients replaces the bitwarden client constructor with one that records every created client.
+func trackClients(t *testing.T, loginErr error) *[]*closeTrackingClient {
+	t.Helper()
+
+	var (
+		mu      sync.Mutex
+		clients []*closeTrackingClient
+	)
+
+	prev := newBitwardenClientFn
+	newBitwardenClientFn = func(apiURL, identityURL *string) (sdk.BitwardenClientInterface, error) {
+		if loginErr != nil {
+			return nil, loginErr
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		c := &closeTrackingClient{}
+		clients = append(clients, c)
+
+		return c, nil
+	}
+	t.Cleanup(func() {
+		newBitwardenClientFn = prev
+	})
+
+	return &clients
+}
+
+func loginRequest(token string) *LoginRequest {
+	return &LoginRequest{RequestBase: &RequestBase{}, AccessToken: token}
+}
+
+func TestSessionPoolReusesSessions(t *testing.T) {
+	clients := trackClients(t, nil)
+	pool := NewSessionPool(nil, time.Minute, 10)
+	defer pool.Close()
+
+	first, releaseFirst, err := pool.Acquire(context.Background(), loginRequest("token"))
+	require.NoError(t, err)
+	releaseFirst()
+
+	second, releaseSecond, err := pool.Acquire(context.Background(), loginRequest("token"))
+	require.NoError(t, err)
+	releaseSecond()
+
+	other, releaseOther, err := pool.Acquire(context.Background(), loginRequest("other-token"))
+	require.NoError(t, err)
+	releaseOther()
+
+	assert.Same(t, first, second)
+	assert.NotSame(t, first, other)
+	assert.Len(t, *clients, 2)
+	assert.Equal(t, 2, pool.Len())
+}
+
+func TestSessionPoolKeyIncludesURLsAndStatePath(t *testing.T) {
+	base := loginRequest("token")
+	withAPIURL := &LoginRequest{RequestBase: &RequestBase{APIURL: "https://api.example.com"}, AccessToken: "token"}
+	withStatePath := &LoginRequest{RequestBase: &RequestBase{}, AccessToken: "token", StatePath: "/tmp/state"}
+	withDefaults := &LoginRequest{
+		RequestBase: &RequestBase{APIURL: defaultAPIURL, IdentityURL: defaultIdentityURL},
+		AccessToken: "token",
+		StatePath:   defaultStatePath,
+	}
+
+	assert.NotEqual(t, sessionKey(base), sessionKey(withAPIURL))
+	assert.NotEqual(t, sessionKey(base), sessionKey(withStatePath))
+	assert.Equal(t, sessionKey(base), sessionKey(withDefaults))
+	assert.NotContains(t, sessionKey(base), "token")
+}
+
+func TestSessionPoolExpiresIdleSessions(t *testing.T) {
+	clients := trackClients(t, nil)
+	pool := NewSessionPool(nil, time.Minute, 10)
+	defer pool.Close()
+
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	_, release, err := pool.Acquire(context.Background(), loginRequest("token"))
+	require.NoError(t, err)
+	release()
+
+	now = now.Add(2 * time.Minute)
+
+	_, release, err = pool.Acquire(context.Background(), loginRequest("token"))
+	require.NoError(t, err)
+	release()
+
+	require.Len(t, *clients, 2)
+	assert.True(t, (*clients)[0].closed.Load())
+	assert.False(t, (*clients)[1].closed.Load())
+}
+
+func TestSessionPoolEvictsLeastRecentlyUsed(t *testing.T) {
+	clients := trackClients(t, nil)
+	pool := NewSessionPool(nil, time.Minute, 2)
+	defer pool.Close()
+
+	for _, token := range []string{"a", "b", "a", "c"} {
+		_, release, err := pool.Acquire(context.Background(), loginRequest(token))
+		require.NoError(t, err)
+		release()
+	}
+
+	require.Len(t, *clients, 3)
+	assert.False(t, (*clients)[0].closed.Load(), "a was used recently and must be kept")
+	assert.True(t, (*clients)[1].closed.Load(), "b is the least recently used session")
+	assert.False(t, (*clients)[2].closed.Load())
+	assert.Equal(t, 2, pool.Len())
+}
+
+func TestSessionPoolDoesNotCloseSessionsInUse(t *testing.T) {
+	clients := trackClients(t, nil)
+	pool := NewSessionPool(nil, time.Minute, 1)
+
+	_, release, err := pool.Acquire(context.Background(), loginRequest("a"))
+	require.NoError(t, err)
+
+	_, releaseOther, err := pool.Acquire(context.Background(), loginRequest("b"))
+	require.NoError(t, err)
+	releaseOther()
+
+	require.Len(t, *clients, 2)
+	assert.False(t, (*clients)[0].closed.Load(), "evicted session is still in use")
+
+	release()
+	assert.True(t, (*clients)[0].closed.Load())
+
+	pool.Close()
+	assert.True(t, (*clients)[1].closed.Load())
+	assert.Equal(t, 0, pool.Len())
+}
+
+func TestSessionPoolDoesNotCacheFailedLogins(t *testing.T) {
+	trackClients(t, errors.New("boom"))
+	pool := NewSessionPool(nil, time.Minute, 10)
+	defer pool.Close()
+
+	_, _, err := pool.Acquire(context.Background(), loginRequest("token"))
+	require.Error(t, err)
+	assert.Equal(t, 0, pool.Len())
+}
+
+func TestSessionPoolConcurrentAcquire(t *testing.T) {
+	clients := trackClients(t, nil)
+	pool := NewSessionPool(nil, time.Minute, 10)
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			client, release, err := pool.Acquire(context.Background(), loginRequest("token"))
+			assert.NoError(t, err)
+			assert.NotNil(t, client)
+			release()
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, pool.Len())
+
+	pool.Close()
+
+	var open int
+	for _, c := range *clients {
+		if !c.closed.Load() {
+			open++
+		}
+	}
+	assert.Equal(t, 0, open)
+}
+
+func TestWardenWithSessionPool(t *testing.T) {
+	clients := trackClients(t, nil)
+	pool := NewSessionPool(nil, time.Minute, 10)
+	defer pool.Close()
+
+	handler := NewWarden(WardenOptions{Sessions: pool})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotNil(t, r.Context().Value(ContextClientKey))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(WardenHeaderAccessToken, testToken)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Len(t, *clients, 1)
+	assert.False(t, (*clients)[0].closed.Load())
+}
@@ -0,0 +1,72 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrInvalidStatePath is returned when a caller provided state path isn't a file in the state directory.
+var ErrInvalidStatePath = errors.New("invalid state path")
+
+// StatePolicy confines the state files written by the SDK to a directory.
+type StatePolicy struct {
+	// Dir is the directory all state paths are resolved in. If empty, state paths are used as
+	// provided by the caller.
+	Dir string
+	// DerivePath derives the state path from the access token if the caller doesn't provide
+	// one, instead of sharing a single state file between all access tokens.
+	DerivePath bool
+}
+
+// CreateDir creates the state directory if it doesn't exist yet. It is called once at startup,
+// resolving state paths doesn't create any directory.
+func (p *StatePolicy) CreateDir() error {
+	if p == nil || p.Dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(p.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return nil
+}
+
+// Resolve sets the state path of the login request to a file inside the state directory.
+// Caller provided paths must be a single file name, other paths are rejected with
+// ErrInvalidStatePath.
+func (p *StatePolicy) Resolve(req *LoginRequest) error {
+	if p == nil || p.Dir == "" {
+		return nil
+	}
+
+	statePath := req.StatePath
+	switch {
+	case statePath == "" && p.DerivePath:
+		statePath = Fingerprint(req.AccessToken)
+	case statePath == "":
+		statePath = defaultStatePath
+	case statePath == "." || !filepath.IsLocal(statePath) || filepath.Base(statePath) != statePath:
+		return fmt.Errorf("%w: %q must be a file name inside the state directory", ErrInvalidStatePath, statePath)
+	}
+
+	req.StatePath = filepath.Join(p.Dir, statePath)
+
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwarden
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatePolicyResolve(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		policy      *StatePolicy
+		statePath   string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:      "nil policy keeps the path",
+			statePath: "../../etc/passwd",
+			expected:  "../../etc/passwd",
+		},
+		{
+			name:      "no state dir keeps the path",
+			policy:    &StatePolicy{},
+			statePath: "/tmp/state",
+			expected:  "/tmp/state",
+		},
+		{
+			name:      "file name",
+			policy:    &StatePolicy{Dir: dir},
+			statePath: "team-a.state",
+			expected:  filepath.Join(dir, "team-a.state"),
+		},
+		{
+			name:     "default path inside the state dir",
+			policy:   &StatePolicy{Dir: dir},
+			expected: filepath.Join(dir, defaultStatePath),
+		},
+		{
+			name:     "derived path",
+			policy:   &StatePolicy{Dir: dir, DerivePath: true},
+			expected: filepath.Join(dir, Fingerprint(testToken)),
+		},
+		{
+			name:      "provided path wins over derived path",
+			policy:    &StatePolicy{Dir: dir, DerivePath: true},
+			statePath: "state",
+			expected:  filepath.Join(dir, "state"),
+		},
+		{
+			name:        "traversal",
+			policy:      &StatePolicy{Dir: dir},
+			statePath:   "../../etc/state",
+			expectError: true,
+		},
+		{
+			name:        "state dir itself",
+			policy:      &StatePolicy{Dir: dir},
+			statePath:   ".",
+			expectError: true,
+		},
+		{
+			name:        "nested path",
+			policy:      &StatePolicy{Dir: dir},
+			statePath:   "team-a/state",
+			expectError: true,
+		},
+		{
+			name:        "traversal in the middle",
+			policy:      &StatePolicy{Dir: dir},
+			statePath:   "team-a/../../state",
+			expectError: true,
+		},
+		{
+			name:        "absolute path",
+			policy:      &StatePolicy{Dir: dir},
+			statePath:   "/etc/state",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &LoginRequest{RequestBase: &RequestBase{}, AccessToken: testToken, StatePath: tt.statePath}
+
+			err := tt.policy.Resolve(req)
+
+			if tt.expectError {
+				require.ErrorIs(t, err, ErrInvalidStatePath)
+				assert.Equal(t, tt.statePath, req.StatePath)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, req.StatePath)
+			}
+		})
+	}
+}
+
+func TestStatePolicyCreateDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+
+	require.NoError(t, (*StatePolicy)(nil).CreateDir())
+	require.NoError(t, (&StatePolicy{}).CreateDir())
+	require.NoError(t, (&StatePolicy{Dir: dir}).CreateDir())
+	assert.DirExists(t, dir)
+
+	req := &LoginRequest{RequestBase: &RequestBase{}, AccessToken: testToken, StatePath: "state"}
+	require.NoError(t, (&StatePolicy{Dir: filepath.Join(dir, "missing")}).Resolve(req))
+	assert.NoDirExists(t, filepath.Join(dir, "missing"))
+}
+
+func TestWardenResolvesStatePath(t *testing.T) {
+	dir := t.TempDir()
+
+	var statePath string
+	prev := newBitwardenClientFn
+	newBitwardenClientFn = func(_, _ *string) (sdk.BitwardenClientInterface, error) {
+		return &stateRecordingClient{statePath: &statePath}, nil
+	}
+	defer func() {
+		newBitwardenClientFn = prev
+	}()
+
+	handler := NewWarden(WardenOptions{StatePolicy: &StatePolicy{Dir: dir}})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(WardenHeaderAccessToken, testToken)
+	req.Header.Set(WardenHeaderStatePath, "state")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, filepath.Join(dir, "state"), statePath)
+
+	req = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(WardenHeaderAccessToken, testToken)
+	req.Header.Set(WardenHeaderStatePath, "../../etc/state")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid state path")
+}
+
+type stateRecordingClient struct {
+	testClient
+
+	statePath *string
+}
+
+func (c *stateRecordingClient) AccessTokenLogin(_ string, statePath *string) error {
+	*c.statePath = *statePath
+
+	return nil
+}
@@ -0,0 +1,230 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bitwardentest provides a stand-in for the Bitwarden identity and API servers, so the
+// SDK can be tested end to end without a Bitwarden account. Like the real servers, it only
+// handles encrypted secrets and the SDK does all the cryptography.
+package bitwardentest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Paths the identity and API servers are served under.
+const (
+	identityPath = "/identity"
+	apiPath      = "/api"
+)
+
+// Server is a Bitwarden identity and API server of a single organization. Pass IdentityURL and
+// APIURL to the SDK, using the Warden-Identity-Url and Warden-Api-Url headers, and log in with an
+// access token returned by NewAccessToken.
+type Server struct {
+	*httptest.Server
+
+	// OrganizationID is the ID of the organization all access tokens belong to.
+	OrganizationID string
+
+	mu           sync.Mutex
+	orgKey       symmetricKey
+	accessTokens map[string]accessToken
+	tokens       map[string]string
+	projects     map[string]*project
+	secrets      map[string]*secret
+	revision     time.Time
+	requests     []string
+}
+
+// accessToken is a registered machine account access token.
+type accessToken struct {
+	clientSecret string
+	key          symmetricKey
+}
+
+// project is a stored project. The name is encrypted with the organization key.
+type project struct {
+	ID           string
+	Name         string
+	CreationDate time.Time
+	RevisionDate time.Time
+}
+
+// secret is a stored secret. Key, value and note are encrypted with the organization key.
+type secret struct {
+	ID           string
+	Key          string
+	Value        string
+	Note         string
+	ProjectIDs   []string
+	CreationDate time.Time
+	RevisionDate time.Time
+}
+
+// NewServer starts and returns a new server, which is closed when the test finishes. The SDK only
+// connects using https, so the test trusts the CA of the server by setting SSL_CERT_FILE. Tests
+// using a server can't run in parallel.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	ca, err := testCA()
+	if err != nil {
+		t.Fatalf("failed to create certificate authority: %v", err)
+	}
+
+	tlsConfig, err := ca.serverConfig()
+	if err != nil {
+		t.Fatalf("failed to create server certificate: %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, ca.pem, 0o600); err != nil {
+		t.Fatalf("failed to write certificate authority: %v", err)
+	}
+	t.Setenv("SSL_CERT_FILE", caFile)
+
+	s := &Server{
+		OrganizationID: uuid.NewString(),
+		orgKey:         newSymmetricKey(),
+		accessTokens:   make(map[string]accessToken),
+		tokens:         make(map[string]string),
+		projects:       make(map[string]*project),
+		secrets:        make(map[string]*secret),
+	}
+	s.revision = time.Now().UTC()
+	s.Server = httptest.NewUnstartedServer(s.routes())
+	s.TLS = tlsConfig
+	s.StartTLS()
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// IdentityURL returns the URL of the identity server.
+func (s *Server) IdentityURL() string {
+	return s.URL + identityPath
+}
+
+// APIURL returns the URL of the API server.
+func (s *Server) APIURL() string {
+	return s.URL + apiPath
+}
+
+// NewAccessToken registers a new machine account with access to the organization and returns
+// its access token.
+func (s *Server) NewAccessToken() string {
+	secret := make([]byte, 16)
+	_, _ = rand.Read(secret)
+
+	key, err := deriveShareableKey(secret, "accesstoken", "sm-access-token")
+	if err != nil {
+		panic(err)
+	}
+
+	id := uuid.NewString()
+	clientSecret := randomString(30)
+
+	s.mu.Lock()
+	s.accessTokens[id] = accessToken{clientSecret: clientSecret, key: key}
+	s.mu.Unlock()
+
+	return "0." + id + "." + clientSecret + ":" + b64(secret)
+}
+
+// AddProject stores a project and returns its ID.
+func (s *Server) AddProject(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	p := &project{ID: uuid.NewString(), Name: s.orgKey.encrypt([]byte(name)), CreationDate: now, RevisionDate: now}
+	s.projects[p.ID] = p
+
+	return p.ID
+}
+
+// AddSecret stores a secret and returns its ID.
+func (s *Server) AddSecret(key, value, note string, projectIDs ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	sec := &secret{
+		ID:           uuid.NewString(),
+		Key:          s.orgKey.encrypt([]byte(key)),
+		Value:        s.orgKey.encrypt([]byte(value)),
+		Note:         s.orgKey.encrypt([]byte(note)),
+		ProjectIDs:   projectIDs,
+		CreationDate: now,
+		RevisionDate: now,
+	}
+	s.secrets[sec.ID] = sec
+	s.revision = now
+
+	return sec.ID
+}
+
+// Secret returns the decrypted key, value and note of a stored secret.
+func (s *Server) Secret(id string) (key, value, note string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec, ok := s.secrets[id]
+	if !ok {
+		return "", "", "", false
+	}
+
+	return s.decryptString(sec.Key), s.decryptString(sec.Value), s.decryptString(sec.Note), true
+}
+
+// Requests returns the method and path of every request the server received, like
+// `GET /api/secrets/<id>`.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) decryptString(encrypted string) string {
+	plain, err := s.orgKey.decrypt(encrypted)
+	if err != nil {
+		return ""
+	}
+
+	return string(plain)
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)[:n]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,158 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwardentest
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+func login(t *testing.T, s *Server, accessToken string) (sdk.BitwardenClientInterface, error) {
+	t.Helper()
+
+	client, err := bitwarden.Login(context.Background(), &bitwarden.LoginRequest{
+		RequestBase: &bitwarden.RequestBase{APIURL: s.APIURL(), IdentityURL: s.IdentityURL()},
+		AccessToken: accessToken,
+		StatePath:   filepath.Join(t.TempDir(), "state"),
+	})
+	if err == nil {
+		t.Cleanup(client.Close)
+	}
+
+	return client, err
+}
+
+func TestSecrets(t *testing.T) {
+	s := NewServer(t)
+	projectID := s.AddProject("project")
+	secretID := s.AddSecret("key", "value", "note", projectID)
+
+	client, err := login(t, s, s.NewAccessToken())
+	require.NoError(t, err)
+
+	secret, err := client.Secrets().Get(secretID)
+	require.NoError(t, err)
+	assert.Equal(t, "key", secret.Key)
+	assert.Equal(t, "value", secret.Value)
+	assert.Equal(t, "note", secret.Note)
+	assert.Equal(t, s.OrganizationID, secret.OrganizationID)
+	assert.Equal(t, projectID, *secret.ProjectID)
+
+	secrets, err := client.Secrets().GetByIDS([]string{secretID})
+	require.NoError(t, err)
+	assert.Len(t, secrets.Data, 1)
+
+	identifiers, err := client.Secrets().List(s.OrganizationID)
+	require.NoError(t, err)
+	assert.Equal(t, []sdk.SecretIdentifierResponse{{ID: secretID, Key: "key", OrganizationID: s.OrganizationID, ProjectIDS: []string{projectID}}}, identifiers.Data)
+
+	created, err := client.Secrets().Create("created", "created value", "", s.OrganizationID, []string{projectID})
+	require.NoError(t, err)
+	key, value, _, ok := s.Secret(created.ID)
+	require.True(t, ok)
+	assert.Equal(t, []string{"created", "created value"}, []string{key, value})
+
+	_, err = client.Secrets().Update(created.ID, "created", "updated value", "", s.OrganizationID, []string{projectID})
+	require.NoError(t, err)
+	_, value, _, _ = s.Secret(created.ID)
+	assert.Equal(t, "updated value", value)
+
+	deleted, err := client.Secrets().Delete([]string{created.ID})
+	require.NoError(t, err)
+	assert.Nil(t, deleted.Data[0].Error)
+
+	_, err = client.Secrets().Get(created.ID)
+	assert.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestSync(t *testing.T) {
+	s := NewServer(t)
+	s.AddSecret("key", "value", "")
+
+	client, err := login(t, s, s.NewAccessToken())
+	require.NoError(t, err)
+
+	response, err := client.Secrets().Sync(s.OrganizationID, nil)
+	require.NoError(t, err)
+	assert.True(t, response.HasChanges)
+	assert.Len(t, response.Secrets, 1)
+
+	lastSynced := time.Now()
+	response, err = client.Secrets().Sync(s.OrganizationID, &lastSynced)
+	require.NoError(t, err)
+	assert.False(t, response.HasChanges)
+
+	s.AddSecret("other", "value", "")
+	response, err = client.Secrets().Sync(s.OrganizationID, &lastSynced)
+	require.NoError(t, err)
+	assert.True(t, response.HasChanges)
+	assert.Len(t, response.Secrets, 2)
+}
+
+func TestProjects(t *testing.T) {
+	s := NewServer(t)
+	projectID := s.AddProject("project")
+
+	client, err := login(t, s, s.NewAccessToken())
+	require.NoError(t, err)
+
+	project, err := client.Projects().Get(projectID)
+	require.NoError(t, err)
+	assert.Equal(t, "project", project.Name)
+
+	created, err := client.Projects().Create(s.OrganizationID, "created")
+	require.NoError(t, err)
+
+	updated, err := client.Projects().Update(created.ID, s.OrganizationID, "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", updated.Name)
+
+	projects, err := client.Projects().List(s.OrganizationID)
+	require.NoError(t, err)
+	assert.Len(t, projects.Data, 2)
+
+	deleted, err := client.Projects().Delete([]string{created.ID})
+	require.NoError(t, err)
+	assert.Nil(t, deleted.Data[0].Error)
+}
+
+func TestLoginRejectsUnknownAccessTokens(t *testing.T) {
+	s := NewServer(t)
+
+	_, err := login(t, s, "0.ec2c1d46-6a4b-4751-a310-af9601317f2d.unknown:AAAAAAAAAAAAAAAAAAAAAA==")
+	assert.ErrorContains(t, err, "invalid_client")
+}
+
+func TestAPIRejectsUnauthenticatedRequests(t *testing.T) {
+	s := NewServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, s.APIURL()+"/secrets/"+s.AddSecret("key", "value", ""), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer unknown")
+
+	resp, err := s.Client().Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,133 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwardentest
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encStringType is the type of the encrypted strings Bitwarden uses for secrets, AES-256-CBC
+// with an HMAC-SHA256 over the IV and cipher text.
+const encStringType = "2"
+
+// symmetricKey is a Bitwarden AES-256-CBC-HMAC-SHA256 key.
+type symmetricKey struct {
+	enc []byte
+	mac []byte
+}
+
+// newSymmetricKey returns a random key.
+func newSymmetricKey() symmetricKey {
+	b := make([]byte, 64)
+	_, _ = rand.Read(b)
+
+	return symmetricKey{enc: b[:32], mac: b[32:]}
+}
+
+// bytes returns the key as encoded in the payload of the identity response.
+func (k symmetricKey) bytes() []byte {
+	return append(bytes.Clone(k.enc), k.mac...)
+}
+
+// deriveShareableKey derives the key of an access token from the secret in it, like Bitwarden.
+func deriveShareableKey(secret []byte, name, info string) (symmetricKey, error) {
+	prk := hmac.New(sha256.New, []byte("bitwarden-"+name))
+	prk.Write(secret)
+
+	b, err := hkdf.Expand(sha256.New, prk.Sum(nil), info, 64)
+	if err != nil {
+		return symmetricKey{}, err
+	}
+
+	return symmetricKey{enc: b[:32], mac: b[32:]}, nil
+}
+
+// encrypt returns plain text encrypted as an encrypted string.
+func (k symmetricKey) encrypt(plain []byte) string {
+	iv := make([]byte, aes.BlockSize)
+	_, _ = rand.Read(iv)
+
+	padding := aes.BlockSize - len(plain)%aes.BlockSize
+	data := append(bytes.Clone(plain), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	block, _ := aes.NewCipher(k.enc)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	return encStringType + "." + b64(iv) + "|" + b64(data) + "|" + b64(k.sign(iv, data))
+}
+
+// decrypt returns the plain text of an encrypted string.
+func (k symmetricKey) decrypt(s string) ([]byte, error) {
+	typ, rest, ok := strings.Cut(s, ".")
+	if !ok || typ != encStringType {
+		return nil, fmt.Errorf("unsupported encrypted string %q", s)
+	}
+
+	parts := strings.Split(rest, "|")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid encrypted string")
+	}
+
+	var decoded [3][]byte
+	for i, part := range parts {
+		b, err := base64.StdEncoding.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encrypted string: %w", err)
+		}
+		decoded[i] = b
+	}
+	iv, data, mac := decoded[0], decoded[1], decoded[2]
+
+	if !hmac.Equal(mac, k.sign(iv, data)) {
+		return nil, errors.New("invalid mac")
+	}
+
+	if len(iv) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid encrypted string")
+	}
+
+	block, _ := aes.NewCipher(k.enc)
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, errors.New("invalid padding")
+	}
+
+	return plain[:len(plain)-padding], nil
+}
+
+func (k symmetricKey) sign(iv, data []byte) []byte {
+	mac := hmac.New(sha256.New, k.mac)
+	mac.Write(iv)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+func b64(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
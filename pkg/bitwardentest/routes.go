@@ -0,0 +1,527 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitwardentest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// tokenLifetime is the lifetime of the bearer tokens issued by the identity server.
+const tokenLifetime = time.Hour
+
+func (s *Server) routes() http.Handler {
+	r := chi.NewRouter()
+	r.Use(s.record)
+
+	r.Post(identityPath+"/connect/token", s.tokenHandler)
+
+	r.Route(apiPath, func(r chi.Router) {
+		r.Use(s.authenticate)
+
+		r.Get("/secrets/{id}", s.getSecretHandler)
+		r.Put("/secrets/{id}", s.updateSecretHandler)
+		r.Post("/secrets/get-by-ids", s.getSecretsByIDsHandler)
+		r.Post("/secrets/delete", s.deleteSecretsHandler)
+		r.Get("/organizations/{orgId}/secrets", s.listSecretsHandler)
+		r.Post("/organizations/{orgId}/secrets", s.createSecretHandler)
+		r.Get("/organizations/{orgId}/secrets/sync", s.syncSecretsHandler)
+
+		r.Get("/projects/{id}", s.getProjectHandler)
+		r.Put("/projects/{id}", s.updateProjectHandler)
+		r.Post("/projects/delete", s.deleteProjectsHandler)
+		r.Get("/organizations/{orgId}/projects", s.listProjectsHandler)
+		r.Post("/organizations/{orgId}/projects", s.createProjectHandler)
+	})
+
+	return r
+}
+
+// record records the requests received, see Requests.
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tokenHandler exchanges the client credentials of an access token for a bearer token and the
+// organization key, encrypted with the key of the access token.
+func (s *Server) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+
+		return
+	}
+
+	s.mu.Lock()
+	token, ok := s.accessTokens[r.PostForm.Get("client_id")]
+	s.mu.Unlock()
+
+	if r.PostForm.Get("grant_type") != "client_credentials" || !ok || token.clientSecret != r.PostForm.Get("client_secret") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+
+		return
+	}
+
+	payload, err := json.Marshal(map[string]string{"encryptionKey": b64(s.orgKey.bytes())})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+
+		return
+	}
+
+	bearer := s.newBearerToken(r.PostForm.Get("client_id"))
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":      bearer,
+		"expires_in":        int(tokenLifetime.Seconds()),
+		"token_type":        "Bearer",
+		"scope":             "api.secrets",
+		"encrypted_payload": token.key.encrypt(payload),
+	})
+}
+
+// newBearerToken returns a JWT carrying the claims the SDK reads. It isn't signed, the server
+// only accepts tokens it issued itself.
+func (s *Server) newBearerToken(clientID string) string {
+	now := time.Now()
+	claims, _ := json.Marshal(map[string]any{
+		"iss":          s.IdentityURL(),
+		"nbf":          now.Unix(),
+		"iat":          now.Unix(),
+		"exp":          now.Add(tokenLifetime).Unix(),
+		"sub":          clientID,
+		"client_id":    clientID,
+		"organization": s.OrganizationID,
+		"scope":        []string{"api.secrets"},
+	})
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	token := header + "." + base64.RawURLEncoding.EncodeToString(claims) + "." + randomString(16)
+
+	s.mu.Lock()
+	s.tokens[token] = clientID
+	s.mu.Unlock()
+
+	return token
+}
+
+// authenticate rejects API requests without a bearer token issued by the identity server.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		_, known := s.tokens[token]
+		s.mu.Unlock()
+
+		if !ok || !known {
+			writeError(w, http.StatusUnauthorized, "Unauthorized.")
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// secretRequest is the body of secret creates and updates. Key, value and note are encrypted.
+type secretRequest struct {
+	Key        string   `json:"key"`
+	Value      string   `json:"value"`
+	Note       string   `json:"note"`
+	ProjectIDs []string `json:"projectIds"`
+}
+
+func (s *Server) getSecretHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec, ok := s.secrets[chi.URLParam(r, "id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found.")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.secretResponse(sec))
+}
+
+func (s *Server) getSecretsByIDsHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		IDs []string `json:"ids"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := make([]any, 0, len(body.IDs))
+	for _, id := range body.IDs {
+		sec, ok := s.secrets[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Resource not found.")
+
+			return
+		}
+		data = append(data, s.secretResponse(sec))
+	}
+
+	writeJSON(w, http.StatusOK, listResponse(data))
+}
+
+func (s *Server) listSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.organization(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets := make([]any, 0, len(s.secrets))
+	for _, sec := range s.sortedSecrets() {
+		secrets = append(secrets, map[string]any{
+			"id":             sec.ID,
+			"organizationId": s.OrganizationID,
+			"key":            sec.Key,
+			"creationDate":   sec.CreationDate,
+			"revisionDate":   sec.RevisionDate,
+			"projects":       s.secretProjects(sec),
+			"read":           true,
+			"write":          true,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"object":   "SecretsWithProjectsList",
+		"secrets":  secrets,
+		"projects": []any{},
+	})
+}
+
+func (s *Server) syncSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.organization(w, r) {
+		return
+	}
+
+	var lastSyncedDate time.Time
+	if v := r.URL.Query().Get("lastSyncedDate"); v != "" {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid lastSyncedDate.")
+
+			return
+		}
+		lastSyncedDate = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !lastSyncedDate.IsZero() && !s.revision.After(lastSyncedDate) {
+		writeJSON(w, http.StatusOK, map[string]any{"object": "secretsSync", "hasChanges": false, "secrets": nil})
+
+		return
+	}
+
+	data := make([]any, 0, len(s.secrets))
+	for _, sec := range s.sortedSecrets() {
+		data = append(data, s.secretResponse(sec))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"object": "secretsSync", "hasChanges": true, "secrets": listResponse(data)})
+}
+
+func (s *Server) createSecretHandler(w http.ResponseWriter, r *http.Request) {
+	var body secretRequest
+	if !s.organization(w, r) || !decode(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.validSecretLocked(w, body) {
+		return
+	}
+
+	now := time.Now().UTC()
+	sec := &secret{
+		ID:           uuid.NewString(),
+		Key:          body.Key,
+		Value:        body.Value,
+		Note:         body.Note,
+		ProjectIDs:   body.ProjectIDs,
+		CreationDate: now,
+		RevisionDate: now,
+	}
+	s.secrets[sec.ID] = sec
+	s.revision = now
+
+	writeJSON(w, http.StatusOK, s.secretResponse(sec))
+}
+
+func (s *Server) updateSecretHandler(w http.ResponseWriter, r *http.Request) {
+	var body secretRequest
+	if !decode(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec, ok := s.secrets[chi.URLParam(r, "id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found.")
+
+		return
+	}
+
+	if !s.validSecretLocked(w, body) {
+		return
+	}
+
+	now := time.Now().UTC()
+	sec.Key, sec.Value, sec.Note, sec.ProjectIDs, sec.RevisionDate = body.Key, body.Value, body.Note, body.ProjectIDs, now
+	s.revision = now
+
+	writeJSON(w, http.StatusOK, s.secretResponse(sec))
+}
+
+func (s *Server) deleteSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	if !decode(w, r, &ids) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := make([]any, 0, len(ids))
+	for _, id := range ids {
+		var deleteErr any
+		if _, ok := s.secrets[id]; ok {
+			delete(s.secrets, id)
+			s.revision = time.Now().UTC()
+		} else {
+			deleteErr = "Secret not found."
+		}
+		data = append(data, map[string]any{"object": "bulkDeleteResponse", "id": id, "error": deleteErr})
+	}
+
+	writeJSON(w, http.StatusOK, listResponse(data))
+}
+
+func (s *Server) getProjectHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.projects[chi.URLParam(r, "id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found.")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.projectResponse(p))
+}
+
+func (s *Server) listProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.organization(w, r) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projects := make([]*project, 0, len(s.projects))
+	for _, p := range s.projects {
+		projects = append(projects, p)
+	}
+	slices.SortFunc(projects, func(a, b *project) int { return strings.Compare(a.ID, b.ID) })
+
+	data := make([]any, 0, len(projects))
+	for _, p := range projects {
+		data = append(data, s.projectResponse(p))
+	}
+
+	writeJSON(w, http.StatusOK, listResponse(data))
+}
+
+func (s *Server) createProjectHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !s.organization(w, r) || !decode(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	p := &project{ID: uuid.NewString(), Name: body.Name, CreationDate: now, RevisionDate: now}
+	s.projects[p.ID] = p
+
+	writeJSON(w, http.StatusOK, s.projectResponse(p))
+}
+
+func (s *Server) updateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.projects[chi.URLParam(r, "id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Resource not found.")
+
+		return
+	}
+
+	p.Name, p.RevisionDate = body.Name, time.Now().UTC()
+
+	writeJSON(w, http.StatusOK, s.projectResponse(p))
+}
+
+func (s *Server) deleteProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	if !decode(w, r, &ids) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := make([]any, 0, len(ids))
+	for _, id := range ids {
+		var deleteErr any
+		if _, ok := s.projects[id]; ok {
+			delete(s.projects, id)
+		} else {
+			deleteErr = "Project not found."
+		}
+		data = append(data, map[string]any{"object": "bulkDeleteResponse", "id": id, "error": deleteErr})
+	}
+
+	writeJSON(w, http.StatusOK, listResponse(data))
+}
+
+// organization rejects requests for organizations other than the one of the server.
+func (s *Server) organization(w http.ResponseWriter, r *http.Request) bool {
+	if chi.URLParam(r, "orgId") != s.OrganizationID {
+		writeError(w, http.StatusNotFound, "Resource not found.")
+
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) validSecretLocked(w http.ResponseWriter, body secretRequest) bool {
+	for _, id := range body.ProjectIDs {
+		if _, ok := s.projects[id]; !ok {
+			writeError(w, http.StatusNotFound, "Resource not found.")
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) sortedSecrets() []*secret {
+	secrets := make([]*secret, 0, len(s.secrets))
+	for _, sec := range s.secrets {
+		secrets = append(secrets, sec)
+	}
+	slices.SortFunc(secrets, func(a, b *secret) int { return strings.Compare(a.ID, b.ID) })
+
+	return secrets
+}
+
+func (s *Server) secretResponse(sec *secret) map[string]any {
+	return map[string]any{
+		"object":         "secret",
+		"id":             sec.ID,
+		"organizationId": s.OrganizationID,
+		"key":            sec.Key,
+		"value":          sec.Value,
+		"note":           sec.Note,
+		"creationDate":   sec.CreationDate,
+		"revisionDate":   sec.RevisionDate,
+		"projects":       s.secretProjects(sec),
+		"read":           true,
+		"write":          true,
+	}
+}
+
+func (s *Server) secretProjects(sec *secret) []any {
+	projects := make([]any, 0, len(sec.ProjectIDs))
+	for _, id := range sec.ProjectIDs {
+		if p, ok := s.projects[id]; ok {
+			projects = append(projects, map[string]any{"id": p.ID, "name": p.Name})
+		}
+	}
+
+	return projects
+}
+
+func (s *Server) projectResponse(p *project) map[string]any {
+	return map[string]any{
+		"object":         "project",
+		"id":             p.ID,
+		"organizationId": s.OrganizationID,
+		"name":           p.Name,
+		"creationDate":   p.CreationDate,
+		"revisionDate":   p.RevisionDate,
+		"read":           true,
+		"write":          true,
+	}
+}
+
+func listResponse(data []any) map[string]any {
+	return map[string]any{"object": "list", "data": data, "continuationToken": nil}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body.")
+
+		return false
+	}
+
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"object": "error", "message": message})
+}
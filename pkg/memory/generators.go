@@ -0,0 +1,128 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/bitwarden/sdk-go/v2"
+)
+
+// Character sets of generated passwords, matching the ones of Bitwarden.
+const (
+	lowercaseChars = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	numberChars    = "0123456789"
+	specialChars   = "!@#$%^&*"
+	ambiguousChars = "IOl01"
+)
+
+// Limits of the password length enforced by Bitwarden.
+const (
+	minPasswordLength = 4
+	maxPasswordLength = 128
+)
+
+// generators implements the generators of the SDK using crypto/rand.
+type generators struct{}
+
+// GeneratePassword generates a password containing at least the requested minimum of characters
+// of every enabled character set, and at least one of each.
+func (generators) GeneratePassword(request sdk.PasswordGeneratorRequest) (*string, error) {
+	if request.Length < minPasswordLength || request.Length > maxPasswordLength {
+		return nil, fmt.Errorf("password length must be between %d and %d", minPasswordLength, maxPasswordLength)
+	}
+
+	sets := []struct {
+		enabled bool
+		chars   string
+		minimum *int64
+	}{
+		{request.Lowercase, lowercaseChars, request.MinLowercase},
+		{request.Uppercase, uppercaseChars, request.MinUppercase},
+		{request.Numbers, numberChars, request.MinNumber},
+		{request.Special, specialChars, request.MinSpecial},
+	}
+
+	var (
+		password []byte
+		all      string
+	)
+	for _, set := range sets {
+		if !set.enabled {
+			continue
+		}
+
+		chars := set.chars
+		if request.AvoidAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+
+				return r
+			}, chars)
+		}
+		all += chars
+
+		minimum := int64(1)
+		if set.minimum != nil {
+			minimum = max(*set.minimum, 1)
+		}
+		for range minimum {
+			password = append(password, randomChar(chars))
+		}
+	}
+
+	if all == "" {
+		return nil, errors.New("at least one character set must be enabled")
+	}
+
+	if int64(len(password)) > request.Length {
+		return nil, errors.New("password length must be greater than the sum of all the minimums")
+	}
+
+	for int64(len(password)) < request.Length {
+		password = append(password, randomChar(all))
+	}
+
+	// Shuffle so the characters of the minimums aren't at the start.
+	for i := len(password) - 1; i > 0; i-- {
+		j := randomInt(i + 1)
+		password[i], password[j] = password[j], password[i]
+	}
+
+	result := string(password)
+
+	return &result, nil
+}
+
+func randomChar(chars string) byte {
+	return chars[randomInt(len(chars))]
+}
+
+func randomInt(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// crypto/rand doesn't fail on supported platforms.
+		panic(err)
+	}
+
+	return int(v.Int64())
+}
@@ -0,0 +1,100 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package memory implements a backend keeping organizations, projects and secrets in memory. It
+// behaves like Bitwarden Secrets Manager without needing an account, for local development and
+// tests. Everything is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/google/uuid"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// Backend is a bitwarden.Backend serving every caller from the same in-memory store. Any access
+// token is accepted.
+type Backend struct {
+	store *store
+}
+
+var _ bitwarden.Backend = &Backend{}
+
+// New returns an empty in-memory backend. Organizations are created when the first project or
+// secret is created in them.
+func New() *Backend {
+	return &Backend{store: newStore(time.Now)}
+}
+
+// Login returns a client of the store. It only rejects empty access tokens.
+func (b *Backend) Login(_ context.Context, req *bitwarden.LoginRequest) (sdk.BitwardenClientInterface, error) {
+	if req.AccessToken == "" {
+		return nil, errors.New("bitwarden login: invalid access token")
+	}
+
+	return &client{store: b.store}, nil
+}
+
+// client implements the SDK client on top of the store.
+type client struct {
+	store *store
+}
+
+func (c *client) AccessTokenLogin(_ string, _ *string) error { return nil }
+func (c *client) Projects() sdk.ProjectsInterface            { return &projects{store: c.store} }
+func (c *client) Secrets() sdk.SecretsInterface              { return &secrets{store: c.store} }
+func (c *client) Generators() sdk.GeneratorsInterface        { return generators{} }
+func (c *client) Close()                                     {}
+
+// store holds the projects and secrets of all organizations.
+type store struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	secrets  map[string]*secret
+	projects map[string]*sdk.ProjectResponse
+	// revisions holds the time the secrets of an organization last changed, used by Sync.
+	revisions map[string]time.Time
+}
+
+// secret is a stored secret. Unlike the responses of the SDK it keeps all its project IDs.
+type secret struct {
+	sdk.SecretResponse
+
+	projectIDs []string
+}
+
+func newStore(now func() time.Time) *store {
+	return &store{
+		now:       now,
+		secrets:   make(map[string]*secret),
+		projects:  make(map[string]*sdk.ProjectResponse),
+		revisions: make(map[string]time.Time),
+	}
+}
+
+// newID returns a new random ID, formatted like the IDs of Bitwarden.
+func newID() string {
+	return uuid.NewString()
+}
+
+// touchLocked records a change of the secrets of the organization at the given time.
+func (s *store) touchLocked(organizationID string, at time.Time) {
+	s.revisions[organizationID] = at
+}
@@ -0,0 +1,272 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// clock is a time source tests advance manually.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestClient(t *testing.T) (sdk.BitwardenClientInterface, *clock) {
+	t.Helper()
+
+	c := &clock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	b := &Backend{store: newStore(c.Now)}
+	client, err := b.Login(context.Background(), &bitwarden.LoginRequest{AccessToken: "token"})
+	require.NoError(t, err)
+
+	return client, c
+}
+
+func TestLogin(t *testing.T) {
+	_, err := New().Login(context.Background(), &bitwarden.LoginRequest{})
+	assert.ErrorContains(t, err, "invalid access token")
+}
+
+func TestSecrets(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	project, err := client.Projects().Create("org-1", "project")
+	require.NoError(t, err)
+
+	created, err := client.Secrets().Create("key", "value", "note", "org-1", []string{project.ID})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, project.ID, *created.ProjectID)
+
+	got, err := client.Secrets().Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	updated, err := client.Secrets().Update(created.ID, "key", "changed", "", "org-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "changed", updated.Value)
+	assert.Nil(t, updated.ProjectID)
+
+	list, err := client.Secrets().List("org-1")
+	require.NoError(t, err)
+	assert.Equal(t, []sdk.SecretIdentifierResponse{{ID: created.ID, Key: "key", OrganizationID: "org-1", ProjectIDS: []string{}}}, list.Data)
+
+	deleted, err := client.Secrets().Delete([]string{created.ID, "missing"})
+	require.NoError(t, err)
+	require.Len(t, deleted.Data, 2)
+	assert.Nil(t, deleted.Data[0].Error)
+	assert.Equal(t, "secret not found", *deleted.Data[1].Error)
+
+	_, err = client.Secrets().Get(created.ID)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestSecretValidation(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	project, err := client.Projects().Create("org-2", "project")
+	require.NoError(t, err)
+	existing, err := client.Secrets().Create("key", "value", "", "org-1", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		call        func() error
+		expectedErr string
+	}{
+		{
+			name: "missing organization",
+			call: func() error {
+				_, err := client.Secrets().Create("key", "value", "", "", nil)
+				return err
+			},
+			expectedErr: "organization id is required",
+		},
+		{
+			name: "missing key",
+			call: func() error {
+				_, err := client.Secrets().Create("", "value", "", "org-1", nil)
+				return err
+			},
+			expectedErr: "key is required",
+		},
+		{
+			name: "project of another organization",
+			call: func() error {
+				_, err := client.Secrets().Create("key", "value", "", "org-1", []string{project.ID})
+				return err
+			},
+			expectedErr: "not found",
+		},
+		{
+			name: "update in another organization",
+			call: func() error {
+				_, err := client.Secrets().Update(existing.ID, "key", "value", "", "org-2", nil)
+				return err
+			},
+			expectedErr: "doesn't belong to organization",
+		},
+		{
+			name: "get missing secrets by ids",
+			call: func() error {
+				_, err := client.Secrets().GetByIDS([]string{existing.ID, "missing"})
+				return err
+			},
+			expectedErr: "secret missing not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.call(), tt.expectedErr)
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	client, c := newTestClient(t)
+
+	response, err := client.Secrets().Sync("org-1", nil)
+	require.NoError(t, err)
+	assert.True(t, response.HasChanges)
+	assert.Empty(t, response.Secrets)
+
+	created, err := client.Secrets().Create("key", "value", "", "org-1", nil)
+	require.NoError(t, err)
+	lastSynced := c.now
+
+	c.now = c.now.Add(time.Minute)
+	response, err = client.Secrets().Sync("org-1", &lastSynced)
+	require.NoError(t, err)
+	assert.False(t, response.HasChanges)
+
+	_, err = client.Secrets().Update(created.ID, "key", "changed", "", "org-1", nil)
+	require.NoError(t, err)
+	response, err = client.Secrets().Sync("org-1", &lastSynced)
+	require.NoError(t, err)
+	assert.True(t, response.HasChanges)
+	require.Len(t, response.Secrets, 1)
+	assert.Equal(t, "changed", response.Secrets[0].Value)
+
+	response, err = client.Secrets().Sync("org-2", &lastSynced)
+	require.NoError(t, err)
+	assert.False(t, response.HasChanges)
+}
+
+func TestProjects(t *testing.T) {
+	client, c := newTestClient(t)
+
+	b, err := client.Projects().Create("org-1", "b")
+	require.NoError(t, err)
+	a, err := client.Projects().Create("org-1", "a")
+	require.NoError(t, err)
+	_, err = client.Projects().Create("org-2", "other")
+	require.NoError(t, err)
+	secret, err := client.Secrets().Create("key", "value", "", "org-1", []string{b.ID})
+	require.NoError(t, err)
+
+	list, err := client.Projects().List("org-1")
+	require.NoError(t, err)
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, []string{a.ID, b.ID}, []string{list.Data[0].ID, list.Data[1].ID})
+
+	c.now = c.now.Add(time.Minute)
+	updated, err := client.Projects().Update(a.ID, "org-1", "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", updated.Name)
+	assert.Equal(t, c.now, updated.RevisionDate)
+
+	_, err = client.Projects().Update(a.ID, "org-1", "")
+	assert.ErrorContains(t, err, "name is required")
+
+	deleted, err := client.Projects().Delete([]string{b.ID})
+	require.NoError(t, err)
+	assert.Nil(t, deleted.Data[0].Error)
+
+	_, err = client.Projects().Get(b.ID)
+	assert.ErrorContains(t, err, "not found")
+
+	got, err := client.Secrets().Get(secret.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.ProjectID)
+}
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     sdk.PasswordGeneratorRequest
+		allowed     string
+		expectedErr string
+	}{
+		{
+			name:    "all character sets",
+			request: sdk.PasswordGeneratorRequest{Length: 32, Lowercase: true, Uppercase: true, Numbers: true, Special: true},
+			allowed: lowercaseChars + uppercaseChars + numberChars + specialChars,
+		},
+		{
+			name:    "numbers without ambiguous characters",
+			request: sdk.PasswordGeneratorRequest{Length: 16, Numbers: true, AvoidAmbiguous: true},
+			allowed: "23456789",
+		},
+		{
+			name:    "minimum of special characters",
+			request: sdk.PasswordGeneratorRequest{Length: 8, Lowercase: true, Special: true, MinSpecial: new(int64(7))},
+			allowed: lowercaseChars + specialChars,
+		},
+		{
+			name:        "too short",
+			request:     sdk.PasswordGeneratorRequest{Length: 3, Lowercase: true},
+			expectedErr: "password length must be between",
+		},
+		{
+			name:        "no character set",
+			request:     sdk.PasswordGeneratorRequest{Length: 16},
+			expectedErr: "at least one character set",
+		},
+		{
+			name:        "minimums exceeding the length",
+			request:     sdk.PasswordGeneratorRequest{Length: 4, Numbers: true, MinNumber: new(int64(5))},
+			expectedErr: "greater than the sum of all the minimums",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := generators{}.GeneratePassword(tt.request)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, *password, int(tt.request.Length))
+			for _, r := range *password {
+				assert.True(t, strings.ContainsRune(tt.allowed, r), "unexpected character %q", r)
+			}
+		})
+	}
+}
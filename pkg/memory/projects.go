@@ -0,0 +1,158 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+)
+
+// projects implements the projects of the SDK on top of the store.
+type projects struct {
+	store *store
+}
+
+func (p *projects) Create(organizationID, name string) (*sdk.ProjectResponse, error) {
+	if err := validateProject(organizationID, name); err != nil {
+		return nil, err
+	}
+
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	now := p.store.now().UTC()
+	project := &sdk.ProjectResponse{
+		ID:             newID(),
+		OrganizationID: organizationID,
+		Name:           name,
+		CreationDate:   now,
+		RevisionDate:   now,
+	}
+	p.store.projects[project.ID] = project
+
+	response := *project
+
+	return &response, nil
+}
+
+func (p *projects) List(organizationID string) (*sdk.ProjectsResponse, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	response := &sdk.ProjectsResponse{Data: []sdk.ProjectResponse{}}
+	for _, project := range p.store.projects {
+		if project.OrganizationID == organizationID {
+			response.Data = append(response.Data, *project)
+		}
+	}
+
+	slices.SortFunc(response.Data, func(a, b sdk.ProjectResponse) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+
+	return response, nil
+}
+
+func (p *projects) Get(projectID string) (*sdk.ProjectResponse, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	project, ok := p.store.projects[projectID]
+	if !ok {
+		return nil, fmt.Errorf("project %s not found", projectID)
+	}
+
+	response := *project
+
+	return &response, nil
+}
+
+func (p *projects) Update(projectID, organizationID, name string) (*sdk.ProjectResponse, error) {
+	if err := validateProject(organizationID, name); err != nil {
+		return nil, err
+	}
+
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	project, ok := p.store.projects[projectID]
+	if !ok {
+		return nil, fmt.Errorf("project %s not found", projectID)
+	}
+
+	if project.OrganizationID != organizationID {
+		return nil, fmt.Errorf("project %s doesn't belong to organization %q", projectID, organizationID)
+	}
+
+	project.Name, project.RevisionDate = name, p.store.now().UTC()
+
+	response := *project
+
+	return &response, nil
+}
+
+// Delete deletes the projects. Secrets of deleted projects are kept without the project, like
+// Bitwarden does.
+func (p *projects) Delete(projectIDs []string) (*sdk.ProjectsDeleteResponse, error) {
+	p.store.mu.Lock()
+	defer p.store.mu.Unlock()
+
+	now := p.store.now().UTC()
+	response := &sdk.ProjectsDeleteResponse{Data: make([]sdk.ProjectDeleteResponse, 0, len(projectIDs))}
+	for _, id := range projectIDs {
+		result := sdk.ProjectDeleteResponse{ID: id}
+		if _, ok := p.store.projects[id]; ok {
+			delete(p.store.projects, id)
+			p.store.unassignProjectLocked(id, now)
+		} else {
+			msg := "project not found"
+			result.Error = &msg
+		}
+		response.Data = append(response.Data, result)
+	}
+
+	return response, nil
+}
+
+// unassignProjectLocked removes a deleted project from the secrets it was assigned to.
+func (s *store) unassignProjectLocked(projectID string, at time.Time) {
+	for _, stored := range s.secrets {
+		if !slices.Contains(stored.projectIDs, projectID) {
+			continue
+		}
+
+		stored.projectIDs = slices.DeleteFunc(stored.projectIDs, func(id string) bool { return id == projectID })
+		stored.ProjectID = firstProjectID(stored.projectIDs)
+		stored.RevisionDate = at
+		s.touchLocked(stored.OrganizationID, at)
+	}
+}
+
+func validateProject(organizationID, name string) error {
+	if organizationID == "" {
+		return errors.New("organization id is required")
+	}
+
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+)
+
+// secrets implements the secrets of the SDK on top of the store.
+type secrets struct {
+	store *store
+}
+
+func (s *secrets) Create(key, value, note, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if err := s.store.validateSecretLocked(key, organizationID, projectIDs); err != nil {
+		return nil, err
+	}
+
+	now := s.store.now().UTC()
+	stored := &secret{
+		SecretResponse: sdk.SecretResponse{
+			ID:             newID(),
+			OrganizationID: organizationID,
+			Key:            key,
+			Value:          value,
+			Note:           note,
+			CreationDate:   now,
+			RevisionDate:   now,
+		},
+		projectIDs: slices.Clone(projectIDs),
+	}
+	stored.ProjectID = firstProjectID(stored.projectIDs)
+	s.store.secrets[stored.ID] = stored
+	s.store.touchLocked(organizationID, now)
+
+	return stored.response(), nil
+}
+
+func (s *secrets) List(organizationID string) (*sdk.SecretIdentifiersResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	response := &sdk.SecretIdentifiersResponse{Data: []sdk.SecretIdentifierResponse{}}
+	for _, stored := range s.store.organizationSecretsLocked(organizationID) {
+		response.Data = append(response.Data, sdk.SecretIdentifierResponse{
+			ID:             stored.ID,
+			Key:            stored.Key,
+			OrganizationID: stored.OrganizationID,
+			ProjectIDS:     append([]string{}, stored.projectIDs...),
+		})
+	}
+
+	return response, nil
+}
+
+func (s *secrets) Get(secretID string) (*sdk.SecretResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	stored, ok := s.store.secrets[secretID]
+	if !ok {
+		return nil, fmt.Errorf("secret %s not found", secretID)
+	}
+
+	return stored.response(), nil
+}
+
+func (s *secrets) GetByIDS(secretIDs []string) (*sdk.SecretsResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	response := &sdk.SecretsResponse{Data: make([]sdk.SecretResponse, 0, len(secretIDs))}
+	for _, id := range secretIDs {
+		stored, ok := s.store.secrets[id]
+		if !ok {
+			return nil, fmt.Errorf("secret %s not found", id)
+		}
+		response.Data = append(response.Data, *stored.response())
+	}
+
+	return response, nil
+}
+
+func (s *secrets) Update(secretID, key, value, note, organizationID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	stored, ok := s.store.secrets[secretID]
+	if !ok {
+		return nil, fmt.Errorf("secret %s not found", secretID)
+	}
+
+	if stored.OrganizationID != organizationID {
+		return nil, fmt.Errorf("secret %s doesn't belong to organization %q", secretID, organizationID)
+	}
+
+	if err := s.store.validateSecretLocked(key, organizationID, projectIDs); err != nil {
+		return nil, err
+	}
+
+	now := s.store.now().UTC()
+	stored.Key, stored.Value, stored.Note, stored.RevisionDate = key, value, note, now
+	stored.projectIDs = slices.Clone(projectIDs)
+	stored.ProjectID = firstProjectID(stored.projectIDs)
+	s.store.touchLocked(organizationID, now)
+
+	return stored.response(), nil
+}
+
+func (s *secrets) Delete(secretIDs []string) (*sdk.SecretsDeleteResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	now := s.store.now().UTC()
+	response := &sdk.SecretsDeleteResponse{Data: make([]sdk.SecretDeleteResponse, 0, len(secretIDs))}
+	for _, id := range secretIDs {
+		result := sdk.SecretDeleteResponse{ID: id}
+		if stored, ok := s.store.secrets[id]; ok {
+			delete(s.store.secrets, id)
+			s.store.touchLocked(stored.OrganizationID, now)
+		} else {
+			msg := "secret not found"
+			result.Error = &msg
+		}
+		response.Data = append(response.Data, result)
+	}
+
+	return response, nil
+}
+
+// Sync returns all secrets of the organization if any of them changed since the last synced
+// date, like Bitwarden does.
+func (s *secrets) Sync(organizationID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	revision, ok := s.store.revisions[organizationID]
+	if lastSyncedDate != nil && (!ok || !revision.After(*lastSyncedDate)) {
+		return &sdk.SecretsSyncResponse{HasChanges: false}, nil
+	}
+
+	response := &sdk.SecretsSyncResponse{HasChanges: true, Secrets: []sdk.SecretResponse{}}
+	for _, stored := range s.store.organizationSecretsLocked(organizationID) {
+		response.Secrets = append(response.Secrets, *stored.response())
+	}
+
+	return response, nil
+}
+
+// validateSecretLocked checks that a secret can be stored with the given values.
+func (s *store) validateSecretLocked(key, organizationID string, projectIDs []string) error {
+	if organizationID == "" {
+		return errors.New("organization id is required")
+	}
+
+	if key == "" {
+		return errors.New("key is required")
+	}
+
+	for _, id := range projectIDs {
+		project, ok := s.projects[id]
+		if !ok || project.OrganizationID != organizationID {
+			return fmt.Errorf("project %s not found", id)
+		}
+	}
+
+	return nil
+}
+
+// organizationSecretsLocked returns the secrets of an organization sorted by key.
+func (s *store) organizationSecretsLocked(organizationID string) []*secret {
+	var result []*secret
+	for _, stored := range s.secrets {
+		if stored.OrganizationID == organizationID {
+			result = append(result, stored)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *secret) int {
+		return cmp.Or(cmp.Compare(a.Key, b.Key), cmp.Compare(a.ID, b.ID))
+	})
+
+	return result
+}
+
+// response returns a copy of the secret as returned by the SDK.
+func (s *secret) response() *sdk.SecretResponse {
+	response := s.SecretResponse
+	if s.ProjectID != nil {
+		projectID := *s.ProjectID
+		response.ProjectID = &projectID
+	}
+
+	return &response
+}
+
+func firstProjectID(projectIDs []string) *string {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+
+	projectID := projectIDs[0]
+
+	return &projectID
+}
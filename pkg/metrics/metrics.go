@@ -0,0 +1,195 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics exposed by the server.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "bitwarden_sdk_server"
+
+// Results used to label login and SDK call metrics.
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// unmatchedRoute labels requests that didn't match any route, so arbitrary paths can't blow up
+// the number of series.
+const unmatchedRoute = "unmatched"
+
+// Registry holds all metrics of the server next to the Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	requestsTotal = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	requestDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	requestsInFlight = promauto.With(Registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	})
+
+	grpcRequestsTotal = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "Number of gRPC calls by method and code.",
+	}, []string{"method", "code"})
+
+	grpcRequestDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Latency of gRPC calls by method and code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	grpcRequestsInFlight = promauto.With(Registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_in_flight",
+		Help:      "Number of gRPC calls currently being served.",
+	})
+
+	loginDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bitwarden_login_duration_seconds",
+		Help:      "Latency of Bitwarden logins by result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"result"})
+
+	loginFailures = promauto.With(Registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bitwarden_login_failures_total",
+		Help:      "Number of failed Bitwarden logins.",
+	})
+
+	clientsCreated = promauto.With(Registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bitwarden_clients_created_total",
+		Help:      "Number of Bitwarden clients created.",
+	})
+
+	sdkCallDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bitwarden_sdk_call_duration_seconds",
+		Help:      "Latency of Bitwarden SDK calls by resource, operation and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"resource", "operation", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler returns the handler serving the metrics of the Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Middleware records the count, latency and in-flight number of requests. Requests are labeled
+// with their chi route pattern instead of the path, so it has to be used on a chi router.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestsInFlight.Inc()
+		defer requestsInFlight.Dec()
+
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+		requestsTotal.With(labels).Inc()
+		requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// UnaryServerInterceptor records the count, latency and in-flight number of gRPC calls, like
+// Middleware does for http requests. Only registered methods reach interceptors, so the method
+// label is bounded.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	grpcRequestsInFlight.Inc()
+	defer grpcRequestsInFlight.Dec()
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	labels := prometheus.Labels{"method": info.FullMethod, "code": status.Code(err).String()}
+	grpcRequestsTotal.With(labels).Inc()
+	grpcRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+// ObserveLogin records the duration and result of a Bitwarden login that started at start.
+func ObserveLogin(start time.Time, err error) {
+	loginDuration.WithLabelValues(result(err)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		loginFailures.Inc()
+	}
+}
+
+// ClientCreated counts a newly created Bitwarden client.
+func ClientCreated() {
+	clientsCreated.Inc()
+}
+
+// ObserveSDKCall records the duration and result of an SDK call that started at start.
+func ObserveSDKCall(resource, operation string, start time.Time, err error) {
+	sdkCallDuration.WithLabelValues(resource, operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+
+	return resultSuccess
+}
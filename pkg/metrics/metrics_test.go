@@ -0,0 +1,112 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/secret/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		assert.Equal(t, float64(1), testutil.ToFloat64(requestsInFlight))
+		w.WriteHeader(http.StatusNotFound)
+	})
+	r.Get("/ok", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		route  string
+		status string
+	}{
+		{name: "route pattern is used as label", path: "/secret/123", route: "/secret/{id}", status: "404"},
+		{name: "implicit status", path: "/ok", route: "/ok", status: "200"},
+		{name: "unmatched path", path: "/does/not/exist", route: unmatchedRoute, status: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := requestsTotal.WithLabelValues(http.MethodGet, tt.route, tt.status)
+			before := testutil.ToFloat64(counter)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+			assert.Equal(t, float64(0), testutil.ToFloat64(requestsInFlight))
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/bitwarden.v1.SecretsService/GetSecret"}
+	counter := grpcRequestsTotal.WithLabelValues(info.FullMethod, codes.NotFound.String())
+	before := testutil.ToFloat64(counter)
+
+	_, err := UnaryServerInterceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		assert.Equal(t, float64(1), testutil.ToFloat64(grpcRequestsInFlight))
+
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	assert.Equal(t, float64(0), testutil.ToFloat64(grpcRequestsInFlight))
+}
+
+func TestObserveLogin(t *testing.T) {
+	before := testutil.ToFloat64(loginFailures)
+
+	ObserveLogin(time.Now(), nil)
+	assert.Equal(t, before, testutil.ToFloat64(loginFailures))
+
+	ObserveLogin(time.Now(), errors.New("login failed"))
+	assert.Equal(t, before+1, testutil.ToFloat64(loginFailures))
+}
+
+func TestHandler(t *testing.T) {
+	ClientCreated()
+	ObserveSDKCall("secrets", "Get", time.Now(), nil)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	for _, name := range []string{
+		"bitwarden_sdk_server_bitwarden_clients_created_total",
+		`bitwarden_sdk_server_bitwarden_sdk_call_duration_seconds_count{operation="Get",resource="secrets",result="success"}`,
+		"go_goroutines",
+	} {
+		assert.True(t, strings.Contains(body, name), "missing %s", name)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+func TestAuditedHandlers(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(s *Server) http.HandlerFunc
+		query    string
+		body     string
+		expected audit.Event
+	}{
+		{
+			name:     "get secret",
+			handler:  func(s *Server) http.HandlerFunc { return s.getSecretHandler },
+			body:     `{"id": "id-1"}`,
+			expected: audit.Event{Operation: "secret.get", SecretIDs: []string{"id-1"}, Outcome: audit.OutcomeSuccess, Status: http.StatusOK},
+		},
+		{
+			name:     "update secret",
+			handler:  func(s *Server) http.HandlerFunc { return s.updateSecretHandler },
+			body:     `{"id": "id-1", "key": "key", "value": "value", "organizationId": "org-1", "projectIds": ["proj-1"]}`,
+			expected: audit.Event{Operation: "secret.update", SecretIDs: []string{"id-1"}, OrganizationID: "org-1", ProjectIDs: []string{"proj-1"}, Outcome: audit.OutcomeSuccess, Status: http.StatusOK},
+		},
+		{
+			name:     "delete projects",
+			handler:  func(s *Server) http.HandlerFunc { return s.deleteProjectHandler },
+			body:     `{"ids": ["proj-1", "proj-2"]}`,
+			expected: audit.Event{Operation: "projects.delete", ProjectIDs: []string{"proj-1", "proj-2"}, Outcome: audit.OutcomeSuccess, Status: http.StatusOK},
+		},
+		{
+			name:     "dry run",
+			handler:  func(s *Server) http.HandlerFunc { return s.deleteSecretHandler },
+			query:    "?dryRun=true",
+			body:     `{"ids": ["id-1"]}`,
+			expected: audit.Event{Operation: "secrets.delete", DryRun: true, SecretIDs: []string{"id-1"}, Outcome: audit.OutcomeSuccess, Status: http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			l, err := audit.New(audit.Config{Path: path})
+			require.NoError(t, err)
+			defer func() {
+				_ = l.Close()
+			}()
+
+			client := &mockClient{
+				secrets: &mockSecrets{
+					getResp:    &sdk.SecretResponse{ID: "id-1"},
+					updateResp: &sdk.SecretResponse{ID: "id-1"},
+				},
+				projects: &mockProjects{deleteResp: &sdk.ProjectsDeleteResponse{}},
+			}
+			handler := audit.Middleware(l)(tt.handler(NewServer(Config{})))
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, bytes.NewBufferString(tt.body))
+			ctx, done := bitwarden.WithClient(req.Context(), client, "identity", func() {})
+			defer done()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req.WithContext(ctx))
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.NotContains(t, string(content), `"value"`)
+
+			var e audit.Event
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(content))), &e))
+			tt.expected.Seq, tt.expected.Time, tt.expected.Hash, tt.expected.ClientIP = e.Seq, e.Time, e.Hash, e.ClientIP
+			assert.Equal(t, tt.expected, e)
+		})
+	}
+}
+
+func TestAuditedBulkRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := audit.New(audit.Config{Path: path})
+	require.NoError(t, err)
+	defer func() {
+		_ = l.Close()
+	}()
+
+	s := NewServer(Config{BulkConcurrency: 1})
+	handler := audit.Middleware(l)(http.HandlerFunc(s.createSecretsHandler))
+
+	body := `{"atomic": true, "items": [{"organizationId": "org-1", "key": "a", "value": "1"}, {"organizationId": "org-1", "value": "2"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	ctx, done := bitwarden.WithClient(req.Context(), newMemoryClient(t), "identity", func() {})
+	defer done()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response BulkSecretsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Equal(t, bulkRolledBack, response.Data[0].Result)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var e audit.Event
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(content))), &e))
+	assert.Equal(t, []string{response.Data[0].ID}, e.SecretIDs)
+	assert.Equal(t, []string{response.Data[0].ID}, e.RolledBackIDs)
+}
@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/memory"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "login per request", config: Config{}},
+		{name: "session reuse", config: Config{SessionTTL: time.Minute, SessionMaxSize: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Backend = memory.New()
+			s := NewServer(tt.config)
+			if s.sessions != nil {
+				defer s.sessions.Close()
+			}
+			testMemoryBackend(t, s.routes())
+		})
+	}
+}
+
+func testMemoryBackend(t *testing.T, routes http.Handler) {
+	t.Helper()
+
+	do := func(method, path, body string, response any) int {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(bitwarden.WardenHeaderAccessToken, "token")
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, req)
+		if response != nil && w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), response))
+		}
+
+		return w.Code
+	}
+
+	project := &sdk.ProjectResponse{}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, apiV2+"/projects", `{"organizationId": "org-1", "name": "project"}`, project))
+
+	created := &sdk.SecretResponse{}
+	require.Equal(t, http.StatusOK, do(http.MethodPost, apiV2+"/secrets", `{"organizationId": "org-1", "key": "key", "value": "value", "projectIds": ["`+project.ID+`"]}`, created))
+
+	got := &sdk.SecretResponse{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, api+"/secret", `{"id": "`+created.ID+`"}`, got))
+	assert.Equal(t, "value", got.Value)
+
+	list := &sdk.SecretIdentifiersResponse{}
+	require.Equal(t, http.StatusOK, do(http.MethodGet, apiV2+"/organizations/org-1/secrets", "", list))
+	assert.Len(t, list.Data, 1)
+
+	require.Equal(t, http.StatusOK, do(http.MethodDelete, apiV2+"/secrets/"+created.ID, "", nil))
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, apiV2+"/secrets/"+created.ID, "", nil))
+
+	w := httptest.NewRecorder()
+	routes.ServeHTTP(w, httptest.NewRequest(http.MethodGet, apiV2+"/secrets/"+created.ID, nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,405 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+const (
+	// maxBulkItems is the largest number of items a bulk request can contain.
+	maxBulkItems = 1000
+	// defaultBulkConcurrency is the number of items processed at once if Config.BulkConcurrency
+	// isn't set.
+	defaultBulkConcurrency = 4
+)
+
+// Results of the items of a bulk request.
+const (
+	bulkCreated    = "created"
+	bulkUpdated    = "updated"
+	bulkFailed     = "failed"
+	bulkSkipped    = "skipped"
+	bulkRolledBack = "rolledBack"
+)
+
+// errBulkSkipped is the error of items of an atomic request that weren't processed because
+// another item failed.
+var errBulkSkipped = errors.New("skipped, another item of the atomic request failed")
+
+// BulkCreateSecretsRequest creates several secrets in one request.
+type BulkCreateSecretsRequest struct {
+	Items []sdk.SecretCreateRequest `json:"items"`
+	// Atomic deletes the created secrets again if any item fails.
+	Atomic bool `json:"atomic,omitempty"`
+}
+
+// BulkUpdateSecretsRequest updates several secrets in one request.
+type BulkUpdateSecretsRequest struct {
+	Items []sdk.SecretPutRequest `json:"items"`
+	// Atomic restores the updated secrets to their previous state if any item fails.
+	Atomic bool `json:"atomic,omitempty"`
+}
+
+// BulkSecretsResponse contains the results of a bulk request, in the order of its items. A failing
+// item doesn't fail the request, the results have to be checked.
+type BulkSecretsResponse struct {
+	Data []BulkSecretResult `json:"data"`
+	// RolledBack is set if an item of an atomic request failed and the other items were undone.
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// BulkSecretResult is the result of an item of a bulk request.
+type BulkSecretResult struct {
+	// Result is created or updated for successful items and failed for failing ones. Items of an
+	// atomic request that weren't processed are skipped, those that were undone are rolledBack.
+	Result string `json:"result"`
+	// ID is the ID of the secret, unless its creation failed.
+	ID     string              `json:"id,omitempty"`
+	Secret *sdk.SecretResponse `json:"secret,omitempty"`
+	// Error and Code describe why the item failed, or why it couldn't be rolled back.
+	Error *string `json:"error,omitempty"`
+	Code  string  `json:"code,omitempty"`
+}
+
+func validateBulkItems(n int) error {
+	if n == 0 {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing items")
+	}
+
+	if n > maxBulkItems {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "too many items %d, at most %d are allowed", n, maxBulkItems)
+	}
+
+	return nil
+}
+
+// CreateSecrets creates the secrets of the request.
+func (svc *service) CreateSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *BulkCreateSecretsRequest) (*BulkSecretsResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation = "secrets.bulk_create"
+		e.OrganizationID, e.ProjectIDs = bulkScope(request.Items, func(item sdk.SecretCreateRequest) (string, []string) {
+			return item.OrganizationID, item.ProjectIDS
+		})
+	})
+
+	if err := validateBulkItems(len(request.Items)); err != nil {
+		return nil, err
+	}
+
+	secrets := make([]*sdk.SecretResponse, len(request.Items))
+	errs := svc.runBulk(len(request.Items), request.Atomic, func(i int) error {
+		item := request.Items[i]
+		secret, err := c.Secrets().Create(item.Key, item.Value, item.Note, item.OrganizationID, item.ProjectIDS)
+		if err != nil {
+			return fmt.Errorf("failed to create secret: %w", err)
+		}
+		if secret == nil {
+			return apierror.Errorf(http.StatusInternalServerError, apierror.CodeInternal, "failed to create secret: empty response")
+		}
+		secrets[i] = secret
+
+		return nil
+	})
+
+	response := bulkResponse(bulkCreated, secrets, errs)
+	if request.Atomic && slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+		svc.rollbackCreates(c, response)
+	}
+
+	annotateBulkResults(ctx, response)
+
+	return response, nil
+}
+
+// UpdateSecrets updates the secrets of the request. Atomic requests get every secret before
+// updating it, to be able to restore it.
+func (svc *service) UpdateSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *BulkUpdateSecretsRequest) (*BulkSecretsResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation = "secrets.bulk_update"
+		e.OrganizationID, e.ProjectIDs = bulkScope(request.Items, func(item sdk.SecretPutRequest) (string, []string) {
+			return item.OrganizationID, item.ProjectIDS
+		})
+	})
+
+	if err := validateBulkItems(len(request.Items)); err != nil {
+		return nil, err
+	}
+
+	previous := make([]*sdk.SecretResponse, len(request.Items))
+	secrets := make([]*sdk.SecretResponse, len(request.Items))
+	errs := svc.runBulk(len(request.Items), request.Atomic, func(i int) error {
+		item := request.Items[i]
+		if request.Atomic {
+			secret, err := c.Secrets().Get(item.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get secret: %w", err)
+			}
+			if secret == nil {
+				return apierror.Errorf(http.StatusInternalServerError, apierror.CodeInternal, "failed to get secret: empty response")
+			}
+			previous[i] = secret
+		}
+
+		secret, err := c.Secrets().Update(item.ID, item.Key, item.Value, item.Note, item.OrganizationID, item.ProjectIDS)
+		svc.invalidateSecrets(item.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update secret: %w", err)
+		}
+		if secret == nil {
+			return apierror.Errorf(http.StatusInternalServerError, apierror.CodeInternal, "failed to update secret: empty response")
+		}
+		secrets[i] = secret
+
+		return nil
+	})
+
+	response := bulkResponse(bulkUpdated, secrets, errs)
+	for i, item := range request.Items {
+		response.Data[i].ID = item.ID
+	}
+
+	if request.Atomic && slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+		svc.rollbackUpdates(c, response, previous)
+	}
+
+	annotateBulkResults(ctx, response)
+
+	return response, nil
+}
+
+// runBulk calls fn for every item, processing at most the configured number of items at once.
+// It returns the errors of the items. With stopOnError, items that weren't started before an
+// item failed are skipped.
+func (svc *service) runBulk(n int, stopOnError bool, fn func(i int) error) []error {
+	concurrency := svc.bulkConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	errs := make([]error, n)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range n {
+		sem <- struct{}{}
+		if stopOnError && failed.Load() {
+			<-sem
+			errs[i] = errBulkSkipped
+
+			continue
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			if errs[i] = fn(i); errs[i] != nil {
+				failed.Store(true)
+			}
+		})
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// bulkResponse returns the results of the items, result being the result of succeeded items.
+func bulkResponse(result string, secrets []*sdk.SecretResponse, errs []error) *BulkSecretsResponse {
+	response := &BulkSecretsResponse{Data: make([]BulkSecretResult, len(secrets))}
+	for i, secret := range secrets {
+		switch {
+		case errors.Is(errs[i], errBulkSkipped):
+			response.Data[i] = BulkSecretResult{Result: bulkSkipped}
+			response.Data[i].setError(errs[i])
+		case errs[i] != nil:
+			response.Data[i] = BulkSecretResult{Result: bulkFailed}
+			response.Data[i].setError(errs[i])
+		default:
+			response.Data[i] = BulkSecretResult{Result: result, ID: secret.ID, Secret: secret}
+		}
+	}
+
+	return response
+}
+
+func (r *BulkSecretResult) setError(err error) {
+	msg := err.Error()
+	r.Error = &msg
+	_, r.Code = apierror.Classify(err)
+}
+
+// rollbackCreates deletes the secrets created by an atomic request. Secrets that can't be deleted
+// keep their created result and report the error.
+func (svc *service) rollbackCreates(c sdk.BitwardenClientInterface, response *BulkSecretsResponse) {
+	response.RolledBack = true
+
+	var ids []string
+	for _, result := range response.Data {
+		if result.Result == bulkCreated {
+			ids = append(ids, result.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	deleted, err := c.Secrets().Delete(ids)
+	svc.invalidateSecrets(ids...)
+	if err == nil && deleted == nil {
+		err = errors.New("empty response")
+	}
+	errs := map[string]error{}
+	for _, id := range ids {
+		errs[id] = err
+	}
+	if err == nil {
+		for _, d := range deleted.Data {
+			if d.Error != nil {
+				errs[d.ID] = errors.New(*d.Error)
+			}
+		}
+	}
+
+	for i, result := range response.Data {
+		if result.Result != bulkCreated {
+			continue
+		}
+
+		if err := errs[result.ID]; err != nil {
+			response.Data[i].setError(fmt.Errorf("failed to roll back: %w", err))
+
+			continue
+		}
+
+		response.Data[i] = BulkSecretResult{Result: bulkRolledBack, ID: result.ID}
+	}
+}
+
+// rollbackUpdates restores the secrets updated by an atomic request to their previous state.
+// Secrets that can't be restored keep their updated result and report the error.
+func (svc *service) rollbackUpdates(c sdk.BitwardenClientInterface, response *BulkSecretsResponse, previous []*sdk.SecretResponse) {
+	response.RolledBack = true
+
+	for i, result := range response.Data {
+		if result.Result != bulkUpdated {
+			continue
+		}
+
+		secret := previous[i]
+		var projectIDs []string
+		if secret.ProjectID != nil {
+			projectIDs = []string{*secret.ProjectID}
+		}
+
+		restored, err := c.Secrets().Update(secret.ID, secret.Key, secret.Value, secret.Note, secret.OrganizationID, projectIDs)
+		svc.invalidateSecrets(secret.ID)
+		if err != nil {
+			response.Data[i].setError(fmt.Errorf("failed to roll back: %w", err))
+
+			continue
+		}
+
+		response.Data[i] = BulkSecretResult{Result: bulkRolledBack, ID: secret.ID, Secret: restored}
+	}
+}
+
+// bulkScope returns the organization and projects of the items for the audit event. The
+// organization is only returned if every item shares it.
+func bulkScope[T any](items []T, scope func(T) (string, []string)) (string, []string) {
+	var organizationID string
+	var projectIDs []string
+	for i, item := range items {
+		organization, projects := scope(item)
+		if i == 0 {
+			organizationID = organization
+		} else if organization != organizationID {
+			organizationID = ""
+		}
+
+		for _, id := range projects {
+			if !slices.Contains(projectIDs, id) {
+				projectIDs = append(projectIDs, id)
+			}
+		}
+	}
+
+	return organizationID, projectIDs
+}
+
+// annotateBulkResults records the secrets changed by a bulk request in the audit event. Secrets
+// changed and then rolled back by an atomic request are recorded as changed and as rolled back.
+func annotateBulkResults(ctx context.Context, response *BulkSecretsResponse) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.SecretIDs, e.RolledBackIDs = nil, nil
+		for _, result := range response.Data {
+			switch result.Result {
+			case bulkCreated, bulkUpdated:
+				e.SecretIDs = append(e.SecretIDs, result.ID)
+			case bulkRolledBack:
+				e.SecretIDs = append(e.SecretIDs, result.ID)
+				e.RolledBackIDs = append(e.RolledBackIDs, result.ID)
+			}
+		}
+	})
+}
+
+func (s *Server) createSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	request := &BulkCreateSecretsRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanCreateSecrets(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.CreateSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) updateSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	request := &BulkUpdateSecretsRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpdateSecrets(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpdateSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
@@ -0,0 +1,231 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCreateSecrets(t *testing.T) {
+	tests := []struct {
+		name               string
+		path               string
+		body               string
+		expectedStatus     int
+		expectedResults    []string
+		expectedRolledBack bool
+		expectedKeys       []string
+	}{
+		{
+			name:            "create",
+			path:            api + "/secrets/bulk",
+			body:            `{"items": [{"organizationId": "org-1", "key": "a", "value": "1"}, {"organizationId": "org-1", "key": "b", "value": "2"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedResults: []string{bulkCreated, bulkCreated},
+			expectedKeys:    []string{"a", "b"},
+		},
+		{
+			name:            "failing item",
+			path:            apiV2 + "/secrets/bulk",
+			body:            `{"items": [{"organizationId": "org-1", "key": "a", "value": "1"}, {"organizationId": "org-1", "value": "2"}, {"organizationId": "org-1", "key": "c", "value": "3"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedResults: []string{bulkCreated, bulkFailed, bulkCreated},
+			expectedKeys:    []string{"a", "c"},
+		},
+		{
+			name:               "atomic",
+			path:               api + "/secrets/bulk",
+			body:               `{"atomic": true, "items": [{"organizationId": "org-1", "key": "a", "value": "1"}, {"organizationId": "org-1", "value": "2"}, {"organizationId": "org-1", "key": "c", "value": "3"}]}`,
+			expectedStatus:     http.StatusOK,
+			expectedResults:    []string{bulkRolledBack, bulkFailed, bulkSkipped},
+			expectedRolledBack: true,
+		},
+		{
+			name:           "no items",
+			path:           api + "/secrets/bulk",
+			body:           `{"items": []}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many items",
+			path:           api + "/secrets/bulk",
+			body:           `{"items": [` + strings.TrimSuffix(strings.Repeat(`{"organizationId": "org-1", "key": "a"},`, maxBulkItems+1), ",") + `]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient(t)
+			s := NewServer(Config{BulkConcurrency: 1})
+
+			w := serveWithClient(s, client, http.MethodPost, tt.path, tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response BulkSecretsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedRolledBack, response.RolledBack)
+			var results []string
+			for _, result := range response.Data {
+				results = append(results, result.Result)
+				if result.Result == bulkFailed || result.Result == bulkSkipped {
+					assert.NotNil(t, result.Error)
+				}
+			}
+			assert.Equal(t, tt.expectedResults, results)
+
+			secrets, err := client.Secrets().List("org-1")
+			require.NoError(t, err)
+			var keys []string
+			for _, secret := range secrets.Data {
+				keys = append(keys, secret.Key)
+			}
+			assert.ElementsMatch(t, tt.expectedKeys, keys)
+		})
+	}
+}
+
+func TestBulkUpdateSecrets(t *testing.T) {
+	tests := []struct {
+		name               string
+		atomic             bool
+		expectedResults    []string
+		expectedRolledBack bool
+		expectedValues     []string
+	}{
+		{
+			name:            "update",
+			expectedResults: []string{bulkUpdated, bulkFailed, bulkUpdated},
+			expectedValues:  []string{"new a", "new b"},
+		},
+		{
+			name:               "atomic",
+			atomic:             true,
+			expectedResults:    []string{bulkRolledBack, bulkFailed, bulkSkipped},
+			expectedRolledBack: true,
+			expectedValues:     []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient(t)
+			project, err := client.Projects().Create("org-1", "project")
+			require.NoError(t, err)
+			a, err := client.Secrets().Create("a", "a", "", "org-1", []string{project.ID})
+			require.NoError(t, err)
+			b, err := client.Secrets().Create("b", "b", "", "org-1", nil)
+			require.NoError(t, err)
+			s := NewServer(Config{BulkConcurrency: 1})
+
+			body := fmt.Sprintf(`{"atomic": %t, "items": [
+				{"id": %q, "organizationId": "org-1", "key": "a", "value": "new a"},
+				{"id": "unknown", "organizationId": "org-1", "key": "unknown", "value": "new"},
+				{"id": %q, "organizationId": "org-1", "key": "b", "value": "new b"}
+			]}`, tt.atomic, a.ID, b.ID)
+			w := serveWithClient(s, client, http.MethodPut, apiV2+"/secrets/bulk", body)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var response BulkSecretsResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedRolledBack, response.RolledBack)
+			var results []string
+			for _, result := range response.Data {
+				results = append(results, result.Result)
+			}
+			assert.Equal(t, tt.expectedResults, results)
+			assert.Equal(t, "unknown", response.Data[1].ID)
+			assert.Equal(t, "not_found", response.Data[1].Code)
+
+			stored, err := client.Secrets().GetByIDS([]string{a.ID, b.ID})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedValues, []string{stored.Data[0].Value, stored.Data[1].Value})
+			if tt.expectedRolledBack {
+				// Rolled back secrets get their projects back.
+				assert.Equal(t, project.ID, *stored.Data[0].ProjectID)
+			}
+		})
+	}
+}
+
+func TestBulkEmptyResponses(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		// The first secret is created, the second one and the rollback get empty responses.
+		client := &emptySecretsClient{mockClient: &mockClient{}, secrets: &emptySecrets{mockSecrets: &mockSecrets{createResp: &sdk.SecretResponse{ID: "id-1"}}}}
+		body := `{"atomic": true, "items": [{"organizationId": "org-1", "key": "a", "value": "1"}, {"organizationId": "org-1", "key": "b", "value": "2"}]}`
+
+		w := serveWithClient(NewServer(Config{BulkConcurrency: 1}), client, http.MethodPost, apiV2+"/secrets/bulk", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response BulkSecretsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, bulkCreated, response.Data[0].Result)
+		require.NotNil(t, response.Data[0].Error)
+		assert.Contains(t, *response.Data[0].Error, "failed to roll back: empty response")
+		assert.Equal(t, bulkFailed, response.Data[1].Result)
+		assert.Equal(t, "internal", response.Data[1].Code)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		client := &mockClient{secrets: &mockSecrets{getResp: &sdk.SecretResponse{ID: "id-1"}}}
+		body := `{"atomic": true, "items": [{"id": "id-1", "organizationId": "org-1", "key": "a", "value": "1"}]}`
+
+		w := serveWithClient(NewServer(Config{BulkConcurrency: 1}), client, http.MethodPut, apiV2+"/secrets/bulk", body)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response BulkSecretsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, bulkFailed, response.Data[0].Result)
+		assert.Equal(t, "internal", response.Data[0].Code)
+	})
+}
+
+// emptySecretsClient creates a secret once, and returns empty responses afterwards.
+type emptySecretsClient struct {
+	*mockClient
+
+	secrets *emptySecrets
+}
+
+func (c *emptySecretsClient) Secrets() sdk.SecretsInterface { return c.secrets }
+
+type emptySecrets struct {
+	*mockSecrets
+
+	created bool
+}
+
+func (s *emptySecrets) Create(key, value, note, orgID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	if s.created {
+		return nil, nil
+	}
+	s.created = true
+
+	return s.mockSecrets.Create(key, value, note, orgID, projectIDs)
+}
@@ -0,0 +1,297 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// cacheKey partitions cached secrets by the identity of the caller, so a secret fetched
+// with one access token is never served to a caller using a different one.
+type cacheKey struct {
+	identity string
+	id       string
+}
+
+type cacheEntry struct {
+	secret    sdk.SecretResponse
+	fetchedAt time.Time
+}
+
+// secretCache is a read-through cache for secret values. Entries are fresh for ttl. After that
+// they are still served for staleTTL while being refreshed in the background.
+type secretCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	staleTTL   time.Duration
+	entries    map[cacheKey]cacheEntry
+	refreshing map[cacheKey]struct{}
+	lastPurge  time.Time
+	// generation changes on every invalidation, so fetches started before it aren't stored.
+	generation uint64
+	now        func() time.Time
+}
+
+func newSecretCache(ttl, staleTTL time.Duration) *secretCache {
+	return &secretCache{
+		ttl:        ttl,
+		staleTTL:   staleTTL,
+		entries:    make(map[cacheKey]cacheEntry),
+		refreshing: make(map[cacheKey]struct{}),
+		now:        time.Now,
+	}
+}
+
+// lookup returns the cached secrets for the given ids in order. Any id without a usable entry
+// is returned in missing. Ids that are served stale and aren't being refreshed yet are returned
+// in stale and marked as refreshing, the caller is responsible for refreshing them.
+func (c *secretCache) lookup(identity string, ids []string) (secrets []sdk.SecretResponse, missing, stale []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for _, id := range ids {
+		key := cacheKey{identity: identity, id: id}
+		entry, ok := c.entries[key]
+		if !ok {
+			missing = append(missing, id)
+
+			continue
+		}
+
+		age := now.Sub(entry.fetchedAt)
+		switch {
+		case age <= c.ttl:
+		case age <= c.ttl+c.staleTTL:
+			if _, ok := c.refreshing[key]; !ok {
+				c.refreshing[key] = struct{}{}
+				stale = append(stale, id)
+			}
+		default:
+			delete(c.entries, key)
+			missing = append(missing, id)
+
+			continue
+		}
+
+		secrets = append(secrets, entry.secret)
+	}
+
+	return secrets, missing, stale
+}
+
+// currentGeneration returns the generation to pass to store for secrets fetched from now on.
+func (c *secretCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// store adds the secrets to the cache for the given identity unless the cache has been
+// invalidated since generation was obtained.
+func (c *secretCache) store(identity string, generation uint64, secrets ...sdk.SecretResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := c.now()
+	for _, secret := range secrets {
+		c.entries[cacheKey{identity: identity, id: secret.ID}] = cacheEntry{secret: secret, fetchedAt: now}
+	}
+
+	c.purgeLocked(now)
+}
+
+// refreshed clears the refreshing mark of the given ids.
+func (c *secretCache) refreshed(identity string, ids []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		delete(c.refreshing, cacheKey{identity: identity, id: id})
+	}
+}
+
+// invalidate drops the given ids for every identity.
+func (c *secretCache) invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	remove := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		remove[id] = struct{}{}
+	}
+
+	for key := range c.entries {
+		if _, ok := remove[key.id]; ok {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// purgeLocked removes entries that can't be served anymore. It runs at most once per ttl.
+func (c *secretCache) purgeLocked(now time.Time) {
+	if now.Sub(c.lastPurge) < c.ttl {
+		return
+	}
+	c.lastPurge = now
+
+	for key, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) > c.ttl+c.staleTTL {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// getSecret returns a single secret, using the cache if it is enabled.
+func (svc *service) getSecret(ctx context.Context, c sdk.BitwardenClientInterface, id string) (*sdk.SecretResponse, error) {
+	if !svc.cacheEnabled(ctx) {
+		return c.Secrets().Get(id)
+	}
+
+	secrets, err := svc.getCachedSecrets(ctx, c, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("secret %s not found", id)
+	}
+
+	return &secrets[0], nil
+}
+
+// getSecretsByIDs returns the secrets for the given ids, using the cache if it is enabled.
+func (svc *service) getSecretsByIDs(ctx context.Context, c sdk.BitwardenClientInterface, ids []string) (*sdk.SecretsResponse, error) {
+	if !svc.cacheEnabled(ctx) {
+		return c.Secrets().GetByIDS(ids)
+	}
+
+	secrets, err := svc.getCachedSecrets(ctx, c, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.SecretsResponse{Data: secrets}, nil
+}
+
+func (svc *service) cacheEnabled(ctx context.Context) bool {
+	return svc.cache != nil && bitwarden.IdentityFromContext(ctx) != ""
+}
+
+func (svc *service) getCachedSecrets(ctx context.Context, c sdk.BitwardenClientInterface, ids []string) ([]sdk.SecretResponse, error) {
+	identity := bitwarden.IdentityFromContext(ctx)
+
+	cached, missing, stale := svc.cache.lookup(identity, ids)
+	if len(stale) > 0 {
+		svc.revalidate(ctx, c, identity, stale)
+	}
+
+	if len(missing) == 0 {
+		return cached, nil
+	}
+
+	generation := svc.cache.currentGeneration()
+	fetched, err := fetchSecrets(c, missing)
+	if err != nil {
+		return nil, err
+	}
+	svc.cache.store(identity, generation, fetched...)
+
+	return orderSecrets(ids, cached, fetched), nil
+}
+
+// revalidate refreshes stale cache entries in the background.
+func (svc *service) revalidate(ctx context.Context, c sdk.BitwardenClientInterface, identity string, ids []string) {
+	release, ok := bitwarden.Retain(ctx)
+	if !ok {
+		svc.cache.refreshed(identity, ids)
+
+		return
+	}
+
+	generation := svc.cache.currentGeneration()
+	go func() {
+		defer release()
+		defer svc.cache.refreshed(identity, ids)
+
+		secrets, err := fetchSecrets(c, ids)
+		if err != nil {
+			slog.Warn("failed to refresh cached secrets", "error", err)
+
+			return
+		}
+
+		svc.cache.store(identity, generation, secrets...)
+	}()
+}
+
+// invalidateSecrets drops the given ids from the cache if it is enabled.
+func (svc *service) invalidateSecrets(ids ...string) {
+	if svc.cache != nil {
+		svc.cache.invalidate(ids...)
+	}
+}
+
+func fetchSecrets(c sdk.BitwardenClientInterface, ids []string) ([]sdk.SecretResponse, error) {
+	if len(ids) == 1 {
+		secret, err := c.Secrets().Get(ids[0])
+		if err != nil || secret == nil {
+			return nil, err
+		}
+
+		return []sdk.SecretResponse{*secret}, nil
+	}
+
+	secrets, err := c.Secrets().GetByIDS(ids)
+	if err != nil || secrets == nil {
+		return nil, err
+	}
+
+	return secrets.Data, nil
+}
+
+// orderSecrets returns the secrets in the order of the requested ids.
+func orderSecrets(ids []string, sets ...[]sdk.SecretResponse) []sdk.SecretResponse {
+	byID := make(map[string]sdk.SecretResponse, len(ids))
+	for _, set := range sets {
+		for _, secret := range set {
+			byID[secret.ID] = secret
+		}
+	}
+
+	result := make([]sdk.SecretResponse, 0, len(ids))
+	for _, id := range ids {
+		if secret, ok := byID[id]; ok {
+			result = append(result, secret)
+		}
+	}
+
+	return result
+}
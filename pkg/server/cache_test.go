@@ -0,0 +1,258 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// storeSecrets serves secrets from a map and counts the calls made to it.
+type storeSecrets struct {
+	mockSecrets
+
+	mu       sync.Mutex
+	values   map[string]string
+	getCalls int
+	idsCalls int
+	fetched  chan string
+}
+
+func newStoreSecrets(values map[string]string) *storeSecrets {
+	return &storeSecrets{values: values, fetched: make(chan string, 10)}
+}
+
+func (m *storeSecrets) Get(id string) (*sdk.SecretResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.getCalls++
+	value, ok := m.values[id]
+	if !ok {
+		return nil, fmt.Errorf("secret %s not found", id)
+	}
+	select {
+	case m.fetched <- id:
+	default:
+	}
+
+	return &sdk.SecretResponse{ID: id, Value: value}, nil
+}
+
+func (m *storeSecrets) GetByIDS(ids []string) (*sdk.SecretsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.idsCalls++
+	resp := &sdk.SecretsResponse{}
+	for _, id := range ids {
+		resp.Data = append(resp.Data, sdk.SecretResponse{ID: id, Value: m.values[id]})
+	}
+
+	return resp, nil
+}
+
+func (m *storeSecrets) Update(id, key, value, note, orgID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[id] = value
+
+	return &sdk.SecretResponse{ID: id, Value: value}, nil
+}
+
+func (m *storeSecrets) set(id, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[id] = value
+}
+
+func (m *storeSecrets) calls() (get, ids int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.getCalls, m.idsCalls
+}
+
+func cachedRequest(t *testing.T, s *Server, handler http.HandlerFunc, method, body, identity string, secrets *storeSecrets) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+	ctx, done := bitwarden.WithClient(req.Context(), &cacheClient{secrets: secrets}, identity, func() {})
+	defer done()
+	w := httptest.NewRecorder()
+
+	handler(w, req.WithContext(ctx))
+
+	return w
+}
+
+type cacheClient struct {
+	mockClient
+
+	secrets *storeSecrets
+}
+
+func (c *cacheClient) Secrets() sdk.SecretsInterface { return c.secrets }
+
+func secretValue(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp sdk.SecretResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	return resp.Value
+}
+
+func TestSecretCacheServesFreshEntries(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute})
+	secrets := newStoreSecrets(map[string]string{"id-1": "value"})
+
+	for range 3 {
+		w := cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+		assert.Equal(t, "value", secretValue(t, w))
+	}
+
+	get, _ := secrets.calls()
+	assert.Equal(t, 1, get)
+}
+
+func TestSecretCacheIsPartitionedByIdentity(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute})
+	secrets := newStoreSecrets(map[string]string{"id-1": "value"})
+
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-b", secrets)
+
+	get, _ := secrets.calls()
+	assert.Equal(t, 2, get)
+}
+
+func TestSecretCacheWithoutIdentityIsBypassed(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute})
+	secrets := newStoreSecrets(map[string]string{"id-1": "value"})
+
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "", secrets)
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "", secrets)
+
+	get, _ := secrets.calls()
+	assert.Equal(t, 2, get)
+}
+
+func TestSecretCacheStaleWhileRevalidate(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute, SecretCacheStaleTTL: time.Minute})
+	now := time.Now()
+	s.svc.cache.now = func() time.Time { return now }
+	secrets := newStoreSecrets(map[string]string{"id-1": "old"})
+
+	w := cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	assert.Equal(t, "old", secretValue(t, w))
+	<-secrets.fetched
+
+	secrets.set("id-1", "new")
+	now = now.Add(90 * time.Second)
+
+	w = cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	assert.Equal(t, "old", secretValue(t, w), "stale entry is served while refreshing")
+
+	select {
+	case <-secrets.fetched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stale entry was not refreshed")
+	}
+
+	require.Eventually(t, func() bool {
+		w = cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+
+		return secretValue(t, w) == "new"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	now = now.Add(3 * time.Minute)
+	w = cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	assert.Equal(t, "new", secretValue(t, w))
+	get, _ := secrets.calls()
+	assert.Equal(t, 3, get, "expired entries are fetched synchronously")
+}
+
+func TestSecretCacheGetByIDsFetchesOnlyMissing(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute})
+	secrets := newStoreSecrets(map[string]string{"id-1": "one", "id-2": "two", "id-3": "three"})
+
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-2"}`, "tenant-a", secrets)
+	w := cachedRequest(t, s, s.getByIdsSecretHandler, http.MethodGet, `{"ids": ["id-1", "id-2", "id-3"]}`, "tenant-a", secrets)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp sdk.SecretsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 3)
+	assert.Equal(t, []string{"one", "two", "three"}, []string{resp.Data[0].Value, resp.Data[1].Value, resp.Data[2].Value})
+
+	cachedRequest(t, s, s.getByIdsSecretHandler, http.MethodGet, `{"ids": ["id-1", "id-2", "id-3"]}`, "tenant-a", secrets)
+	get, ids := secrets.calls()
+	assert.Equal(t, 1, get)
+	assert.Equal(t, 1, ids)
+}
+
+func TestSecretCacheInvalidatedByUpdate(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute})
+	secrets := newStoreSecrets(map[string]string{"id-1": "old"})
+
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-b", secrets)
+	cachedRequest(t, s, s.updateSecretHandler, http.MethodPut, `{"id": "id-1", "key": "key", "value": "new"}`, "tenant-a", secrets)
+
+	w := cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	assert.Equal(t, "new", secretValue(t, w))
+	w = cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-b", secrets)
+	assert.Equal(t, "new", secretValue(t, w))
+}
+
+func TestSecretCacheInvalidatedByDelete(t *testing.T) {
+	s := NewServer(Config{SecretCacheTTL: time.Minute})
+	secrets := newStoreSecrets(map[string]string{"id-1": "value"})
+	secrets.deleteResp = &sdk.SecretsDeleteResponse{Data: []sdk.SecretDeleteResponse{{ID: "id-1"}}}
+
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+	cachedRequest(t, s, s.deleteSecretHandler, http.MethodDelete, `{"ids": ["id-1"]}`, "tenant-a", secrets)
+	cachedRequest(t, s, s.getSecretHandler, http.MethodGet, `{"id": "id-1"}`, "tenant-a", secrets)
+
+	get, _ := secrets.calls()
+	assert.Equal(t, 2, get)
+}
+
+func TestSecretCacheDropsFetchesStartedBeforeInvalidation(t *testing.T) {
+	c := newSecretCache(time.Minute, 0)
+
+	generation := c.currentGeneration()
+	c.invalidate("id-1")
+	c.store("tenant-a", generation, sdk.SecretResponse{ID: "id-1", Value: "outdated"})
+
+	_, missing, _ := c.lookup("tenant-a", []string{"id-1"})
+	assert.Equal(t, []string{"id-1"}, missing)
+}
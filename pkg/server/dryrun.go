@@ -0,0 +1,579 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+// Actions of a planned change.
+const (
+	planCreate    = "create"
+	planUpdate    = "update"
+	planDelete    = "delete"
+	planUnchanged = "unchanged"
+	planFailed    = "failed"
+)
+
+// Types of the objects a planned change applies to.
+const (
+	planSecret  = "secret"
+	planProject = "project"
+)
+
+// DryRunResponse describes the changes a request would make. Nothing has been changed.
+type DryRunResponse struct {
+	DryRun  bool            `json:"dryRun"`
+	Changes []PlannedChange `json:"changes"`
+}
+
+// PlannedChange is the change a request would make to a secret or project.
+type PlannedChange struct {
+	// Action is create, update, delete or unchanged. Items of bulk requests and IDs of deletes
+	// that would fail are failed.
+	Action string `json:"action"`
+	// Type is secret or project.
+	Type string `json:"type"`
+	// ID is the ID of the secret or project, unless it would be created.
+	ID string `json:"id,omitempty"`
+	// Name is the key of the secret or the name of the project.
+	Name string `json:"name,omitempty"`
+	// Fields are the changed fields. Creates and deletes list every field.
+	Fields []FieldChange `json:"fields,omitempty"`
+	// Error and Code describe why the change would fail.
+	Error *string `json:"error,omitempty"`
+	Code  string  `json:"code,omitempty"`
+}
+
+// FieldChange is the change of a field. Old is missing for creates, New for deletes.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+	// Redacted is set if Old and New are left out because they are secret values, which are only
+	// included if requested.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// dryRun are the options of a dry run.
+type dryRun struct {
+	// withValues includes secret values in the changes.
+	withValues bool
+}
+
+// queryDryRun returns the options of a dry run, requested with the dryRun query parameter. It
+// reports whether the request is a dry run.
+func queryDryRun(r *http.Request) (dryRun, bool, error) {
+	query := r.URL.Query()
+	enabled, err := queryBool(query.Get("dryRun"), "dryRun")
+	if err != nil || !enabled {
+		return dryRun{}, false, err
+	}
+
+	withValues, err := queryBool(query.Get("withValues"), "withValues")
+	if err != nil {
+		return dryRun{}, false, err
+	}
+
+	return dryRun{withValues: withValues}, true, nil
+}
+
+// queryBool parses the value of a boolean query parameter, which is false if empty.
+func queryBool(v, name string) (bool, error) {
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid %s: %w", name, err)
+	}
+
+	return b, nil
+}
+
+// respondDryRun writes the changes returned by plan if the request is a dry run. It reports
+// whether the request has been answered, either with the changes or with an error.
+func (s *Server) respondDryRun(w http.ResponseWriter, r *http.Request, plan func(dryRun) (*DryRunResponse, error)) bool {
+	opts, ok, err := queryDryRun(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return true
+	}
+
+	if !ok {
+		return false
+	}
+
+	audit.Annotate(r.Context(), func(e *audit.Event) {
+		e.DryRun = true
+	})
+
+	response, err := plan(opts)
+	s.respond(w, r, response, err)
+
+	return true
+}
+
+// field is a field of a secret or project compared by a dry run. Value is a string, or the
+// project IDs of a secret.
+type field struct {
+	name      string
+	value     any
+	sensitive bool
+}
+
+func secretFields(key, value, note, organizationID string, projectIDs []string) []field {
+	if projectIDs == nil {
+		projectIDs = []string{}
+	}
+
+	return []field{
+		{name: "key", value: key},
+		{name: "value", value: value, sensitive: true},
+		{name: "note", value: note},
+		{name: "organizationId", value: organizationID},
+		{name: "projectIds", value: projectIDs},
+	}
+}
+
+func currentSecretFields(secret *sdk.SecretResponse) []field {
+	update := secretUpdate(secret)
+
+	return secretFields(update.Key, update.Value, update.Note, update.OrganizationID, update.ProjectIDS)
+}
+
+func projectFields(name, organizationID string) []field {
+	return []field{
+		{name: "name", value: name},
+		{name: "organizationId", value: organizationID},
+	}
+}
+
+// diffFields returns the changes from the fields before to those after, which list the same fields
+// in the same order. Before is nil for creates and after for deletes, every field is returned then.
+func diffFields(before, after []field, opts dryRun) []FieldChange {
+	var changes []FieldChange
+	for i := range max(len(before), len(after)) {
+		var change FieldChange
+		var f field
+		if before != nil {
+			f = before[i]
+			change.Old = f.value
+		}
+		if after != nil {
+			f = after[i]
+			change.New = f.value
+		}
+		change.Field = f.name
+
+		if before != nil && after != nil && sameValue(change.Old, change.New) {
+			continue
+		}
+
+		if f.sensitive && !opts.withValues {
+			change.Old, change.New, change.Redacted = nil, nil, true
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func sameValue(a, b any) bool {
+	if a, ok := a.([]string); ok {
+		b, _ := b.([]string)
+
+		return sameProjects(a, b)
+	}
+
+	return a == b
+}
+
+// plannedUpdate returns the update of the object from before to after, or unchanged if no field
+// differs.
+func plannedUpdate(typ, id, name string, before, after []field, opts dryRun) PlannedChange {
+	change := PlannedChange{Action: planUpdate, Type: typ, ID: id, Name: name, Fields: diffFields(before, after, opts)}
+	if len(change.Fields) == 0 {
+		change.Action = planUnchanged
+	}
+
+	return change
+}
+
+func (c *PlannedChange) setError(err error) {
+	msg := err.Error()
+	c.Action, c.Error = planFailed, &msg
+	_, c.Code = apierror.Classify(err)
+}
+
+// checkSecret validates the fields a secret would be created or updated with, and checks that its
+// projects exist in its organization.
+func checkSecret(c sdk.BitwardenClientInterface, key, organizationID string, projectIDs []string) error {
+	if organizationID == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing organizationId")
+	}
+
+	if key == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing key")
+	}
+
+	for _, id := range projectIDs {
+		project, err := c.Projects().Get(id)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		if project.OrganizationID != organizationID {
+			return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "project %s doesn't belong to organization %s", id, organizationID)
+		}
+	}
+
+	return nil
+}
+
+// checkOrganization fails if an update would move a secret or project to another organization,
+// which Bitwarden doesn't support.
+func checkOrganization(typ, id, current, organizationID string) error {
+	if organizationID != current {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "%s %s doesn't belong to organization %s", typ, id, organizationID)
+	}
+
+	return nil
+}
+
+func (svc *service) planCreateSecret(c sdk.BitwardenClientInterface, request *sdk.SecretCreateRequest, opts dryRun) (PlannedChange, error) {
+	if err := checkSecret(c, request.Key, request.OrganizationID, request.ProjectIDS); err != nil {
+		return PlannedChange{}, err
+	}
+
+	after := secretFields(request.Key, request.Value, request.Note, request.OrganizationID, request.ProjectIDS)
+
+	return PlannedChange{Action: planCreate, Type: planSecret, Name: request.Key, Fields: diffFields(nil, after, opts)}, nil
+}
+
+// planUpdateSecret compares the update to the current secret, fetched from Bitwarden rather than
+// the cache.
+func (svc *service) planUpdateSecret(c sdk.BitwardenClientInterface, request *sdk.SecretPutRequest, opts dryRun) (PlannedChange, error) {
+	if request.ID == "" {
+		return PlannedChange{}, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing id")
+	}
+
+	current, err := c.Secrets().Get(request.ID)
+	if err != nil {
+		return PlannedChange{}, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	return svc.planSecretChange(c, current, request, opts)
+}
+
+// planSecretChange compares the update to the current secret.
+func (svc *service) planSecretChange(c sdk.BitwardenClientInterface, current *sdk.SecretResponse, update *sdk.SecretPutRequest, opts dryRun) (PlannedChange, error) {
+	if err := checkSecret(c, update.Key, update.OrganizationID, update.ProjectIDS); err != nil {
+		return PlannedChange{}, err
+	}
+
+	if err := checkOrganization(planSecret, current.ID, current.OrganizationID, update.OrganizationID); err != nil {
+		return PlannedChange{}, err
+	}
+
+	after := secretFields(update.Key, update.Value, update.Note, update.OrganizationID, update.ProjectIDS)
+
+	return plannedUpdate(planSecret, current.ID, update.Key, currentSecretFields(current), after, opts), nil
+}
+
+// PlanCreateSecret returns the secret CreateSecret would create.
+func (svc *service) PlanCreateSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretCreateRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID, e.ProjectIDs = "secret.create", request.OrganizationID, request.ProjectIDS
+	})
+
+	change, err := svc.planCreateSecret(c, request, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+}
+
+// PlanUpdateSecret returns the changes UpdateSecret would make to the secret.
+func (svc *service) PlanUpdateSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretPutRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs, e.OrganizationID, e.ProjectIDs = "secret.update", []string{request.ID}, request.OrganizationID, request.ProjectIDS
+	})
+
+	change, err := svc.planUpdateSecret(c, request, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+}
+
+// PlanPatchSecret returns the changes PatchSecret would make to the secret.
+func (svc *service) PlanPatchSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *PatchSecretRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs = "secret.patch", []string{request.ID}
+	})
+
+	if request.ID == "" {
+		return nil, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing id")
+	}
+
+	current, err := c.Secrets().Get(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	if err := checkETag(request.IfMatch, current); err != nil {
+		return nil, err
+	}
+
+	update := request.apply(current)
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.OrganizationID, e.ProjectIDs = update.OrganizationID, update.ProjectIDS
+	})
+
+	change, err := svc.planSecretChange(c, current, update, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+}
+
+// PlanUpsertSecret returns whether UpsertSecret would create or update a secret, and the changes
+// it would make.
+func (svc *service) PlanUpsertSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *UpsertSecretRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID, e.ProjectIDs = "secret.upsert", request.OrganizationID, request.ProjectIDs
+	})
+
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	scope := keyScope{organizationID: request.OrganizationID, projectIDs: request.ProjectIDs}
+	matches, err := findByKey(c, scope, request.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) > 1 {
+		return nil, ambiguousKeyError(scope, request.Key, matches)
+	}
+
+	if len(matches) == 0 {
+		change, err := svc.planCreateSecret(c, &sdk.SecretCreateRequest{
+			Key:            request.Key,
+			Value:          request.Value,
+			Note:           request.Note,
+			OrganizationID: request.OrganizationID,
+			ProjectIDS:     request.ProjectIDs,
+		}, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+	}
+
+	id := matches[0].ID
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.SecretIDs = []string{id}
+	})
+
+	existing, err := c.Secrets().Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	projectIDs := request.ProjectIDs
+	if len(projectIDs) == 0 {
+		projectIDs = matches[0].ProjectIDS
+	}
+
+	change, err := svc.planSecretChange(c, existing, &sdk.SecretPutRequest{
+		ID:             id,
+		Key:            request.Key,
+		Value:          request.Value,
+		Note:           request.Note,
+		OrganizationID: request.OrganizationID,
+		ProjectIDS:     projectIDs,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+}
+
+// PlanDeleteSecrets returns the secrets DeleteSecrets would delete. Like DeleteSecrets, IDs that
+// can't be deleted don't fail the request, they are reported as failed.
+func (svc *service) PlanDeleteSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretsDeleteRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs = "secrets.delete", request.IDS
+	})
+
+	return svc.planBulk(len(request.IDS), func(i int) (PlannedChange, error) {
+		secret, err := c.Secrets().Get(request.IDS[i])
+		if err != nil {
+			return PlannedChange{Type: planSecret, ID: request.IDS[i]}, fmt.Errorf("failed to get secret: %w", err)
+		}
+
+		return PlannedChange{Action: planDelete, Type: planSecret, ID: secret.ID, Name: secret.Key, Fields: diffFields(currentSecretFields(secret), nil, opts)}, nil
+	}), nil
+}
+
+// PlanCreateSecrets returns the secrets CreateSecrets would create.
+func (svc *service) PlanCreateSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *BulkCreateSecretsRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation = "secrets.bulk_create"
+		e.OrganizationID, e.ProjectIDs = bulkScope(request.Items, func(item sdk.SecretCreateRequest) (string, []string) {
+			return item.OrganizationID, item.ProjectIDS
+		})
+	})
+
+	if err := validateBulkItems(len(request.Items)); err != nil {
+		return nil, err
+	}
+
+	return svc.planBulk(len(request.Items), func(i int) (PlannedChange, error) {
+		change, err := svc.planCreateSecret(c, &request.Items[i], opts)
+		if err != nil {
+			return PlannedChange{Type: planSecret, Name: request.Items[i].Key}, err
+		}
+
+		return change, nil
+	}), nil
+}
+
+// PlanUpdateSecrets returns the changes UpdateSecrets would make to the secrets.
+func (svc *service) PlanUpdateSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *BulkUpdateSecretsRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation = "secrets.bulk_update"
+		e.OrganizationID, e.ProjectIDs = bulkScope(request.Items, func(item sdk.SecretPutRequest) (string, []string) {
+			return item.OrganizationID, item.ProjectIDS
+		})
+	})
+
+	if err := validateBulkItems(len(request.Items)); err != nil {
+		return nil, err
+	}
+
+	return svc.planBulk(len(request.Items), func(i int) (PlannedChange, error) {
+		change, err := svc.planUpdateSecret(c, &request.Items[i], opts)
+		if err != nil {
+			return PlannedChange{Type: planSecret, ID: request.Items[i].ID, Name: request.Items[i].Key}, err
+		}
+
+		return change, nil
+	}), nil
+}
+
+// planBulk plans every item like runBulk processes them. Failing items are reported as failed.
+func (svc *service) planBulk(n int, plan func(i int) (PlannedChange, error)) *DryRunResponse {
+	changes := make([]PlannedChange, n)
+	errs := svc.runBulk(n, false, func(i int) error {
+		var err error
+		changes[i], err = plan(i)
+
+		return err
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			changes[i].setError(err)
+		}
+	}
+
+	return &DryRunResponse{DryRun: true, Changes: changes}
+}
+
+// PlanCreateProject returns the project CreateProject would create.
+func (svc *service) PlanCreateProject(ctx context.Context, _ sdk.BitwardenClientInterface, request *sdk.ProjectCreateRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID = "project.create", request.OrganizationID
+	})
+
+	if err := checkProject(request.Name, request.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	change := PlannedChange{Action: planCreate, Type: planProject, Name: request.Name, Fields: diffFields(nil, projectFields(request.Name, request.OrganizationID), opts)}
+
+	return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+}
+
+// PlanUpdateProject returns the changes UpdateProject would make to the project.
+func (svc *service) PlanUpdateProject(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.ProjectPutRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.ProjectIDs, e.OrganizationID = "project.update", []string{request.ID}, request.OrganizationID
+	})
+
+	if err := checkProject(request.Name, request.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	current, err := c.Projects().Get(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if err := checkOrganization(planProject, current.ID, current.OrganizationID, request.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	change := plannedUpdate(planProject, current.ID, request.Name, projectFields(current.Name, current.OrganizationID), projectFields(request.Name, request.OrganizationID), opts)
+
+	return &DryRunResponse{DryRun: true, Changes: []PlannedChange{change}}, nil
+}
+
+// PlanDeleteProjects returns the projects DeleteProjects would delete.
+func (svc *service) PlanDeleteProjects(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.ProjectsDeleteRequest, opts dryRun) (*DryRunResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.ProjectIDs = "projects.delete", request.IDS
+	})
+
+	return svc.planBulk(len(request.IDS), func(i int) (PlannedChange, error) {
+		project, err := c.Projects().Get(request.IDS[i])
+		if err != nil {
+			return PlannedChange{Type: planProject, ID: request.IDS[i]}, fmt.Errorf("failed to get project: %w", err)
+		}
+
+		return PlannedChange{Action: planDelete, Type: planProject, ID: project.ID, Name: project.Name, Fields: diffFields(projectFields(project.Name, project.OrganizationID), nil, opts)}, nil
+	}), nil
+}
+
+func checkProject(name, organizationID string) error {
+	if organizationID == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing organizationId")
+	}
+
+	if name == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing name")
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		// expectedChanges lists the action and changed fields of every change.
+		expectedChanges []string
+		// expectedValue is the new value of the first changed value field, empty if redacted.
+		expectedValue string
+	}{
+		{
+			name:            "create",
+			method:          http.MethodPost,
+			path:            api + "/secret?dryRun=true",
+			body:            `{"organizationId": "org-1", "key": "new", "value": "new value", "projectIds": ["{project}"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"create key,value,note,organizationId,projectIds"},
+		},
+		{
+			name:            "create with values",
+			method:          http.MethodPost,
+			path:            apiV2 + "/secrets?dryRun=true&withValues=true",
+			body:            `{"organizationId": "org-1", "key": "new", "value": "new value"}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"create key,value,note,organizationId,projectIds"},
+			expectedValue:   "new value",
+		},
+		{
+			name:           "create in unknown project",
+			method:         http.MethodPost,
+			path:           api + "/secret?dryRun=true",
+			body:           `{"organizationId": "org-1", "key": "new", "value": "new value", "projectIds": ["unknown"]}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "create in project of other organization",
+			method:         http.MethodPost,
+			path:           api + "/secret?dryRun=true",
+			body:           `{"organizationId": "org-2", "key": "new", "value": "new value", "projectIds": ["{project}"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:            "update",
+			method:          http.MethodPut,
+			path:            apiV2 + "/secrets/{id}?dryRun=true",
+			body:            `{"organizationId": "org-1", "key": "key", "value": "changed", "note": "changed", "projectIds": ["{project}"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"update value,note"},
+		},
+		{
+			name:            "update unchanged",
+			method:          http.MethodPut,
+			path:            api + "/secret?dryRun=true",
+			body:            `{"id": "{id}", "organizationId": "org-1", "key": "key", "value": "value", "note": "note", "projectIds": ["{project}"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"unchanged "},
+		},
+		{
+			name:           "update other organization",
+			method:         http.MethodPut,
+			path:           api + "/secret?dryRun=true",
+			body:           `{"id": "{id}", "organizationId": "org-2", "key": "key", "value": "value"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:            "patch",
+			method:          http.MethodPatch,
+			path:            apiV2 + "/secrets/{id}?dryRun=true&withValues=true",
+			body:            `{"value": "changed", "projectIds": null}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"update value,projectIds"},
+			expectedValue:   "changed",
+		},
+		{
+			name:            "upsert existing",
+			method:          http.MethodPut,
+			path:            apiV2 + "/organizations/org-1/secrets/by-key?dryRun=true",
+			body:            `{"key": "key", "value": "changed", "note": "note"}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"update value"},
+		},
+		{
+			name:            "upsert new",
+			method:          http.MethodPut,
+			path:            api + "/secret-by-key?dryRun=true",
+			body:            `{"organizationId": "org-1", "key": "new", "value": "value"}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"create key,value,note,organizationId,projectIds"},
+		},
+		{
+			name:            "delete",
+			method:          http.MethodDelete,
+			path:            apiV2 + "/secrets?ids={id},unknown&dryRun=true",
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"delete key,value,note,organizationId,projectIds", "failed "},
+		},
+		{
+			name:            "bulk create",
+			method:          http.MethodPost,
+			path:            apiV2 + "/secrets/bulk?dryRun=true",
+			body:            `{"items": [{"organizationId": "org-1", "key": "a", "value": "1"}, {"organizationId": "org-1", "value": "2"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"create key,value,note,organizationId,projectIds", "failed "},
+		},
+		{
+			name:            "bulk update",
+			method:          http.MethodPut,
+			path:            api + "/secrets/bulk?dryRun=true",
+			body:            `{"items": [{"id": "{id}", "organizationId": "org-1", "key": "renamed", "value": "value", "note": "note", "projectIds": ["{project}"]}, {"id": "unknown", "organizationId": "org-1", "key": "a"}]}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"update key", "failed "},
+		},
+		{
+			name:            "update project",
+			method:          http.MethodPut,
+			path:            apiV2 + "/projects/{project}?dryRun=true",
+			body:            `{"organizationId": "org-1", "name": "renamed"}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"update name"},
+		},
+		{
+			name:            "delete project",
+			method:          http.MethodDelete,
+			path:            api + "/project?dryRun=true",
+			body:            `{"ids": ["{project}"]}`,
+			expectedStatus:  http.StatusOK,
+			expectedChanges: []string{"delete name,organizationId"},
+		},
+		{
+			name:           "invalid dry run",
+			method:         http.MethodPost,
+			path:           api + "/secret?dryRun=maybe",
+			body:           `{"organizationId": "org-1", "key": "new", "value": "new value"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient(t)
+			project, err := client.Projects().Create("org-1", "project")
+			require.NoError(t, err)
+			secret, err := client.Secrets().Create("key", "value", "note", "org-1", []string{project.ID})
+			require.NoError(t, err)
+
+			replacer := strings.NewReplacer("{id}", secret.ID, "{project}", project.ID)
+			w := serveWithClient(NewServer(Config{}), client, tt.method, replacer.Replace(tt.path), replacer.Replace(tt.body))
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+
+			// Nothing has been changed.
+			stored, err := client.Secrets().Get(secret.ID)
+			require.NoError(t, err)
+			assert.Equal(t, secret, stored)
+			identifiers, err := client.Secrets().List("org-1")
+			require.NoError(t, err)
+			assert.Len(t, identifiers.Data, 1)
+			storedProject, err := client.Projects().Get(project.ID)
+			require.NoError(t, err)
+			assert.Equal(t, project, storedProject)
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response DryRunResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.DryRun)
+
+			var changes []string
+			var value any
+			for _, change := range response.Changes {
+				var fields []string
+				for _, f := range change.Fields {
+					fields = append(fields, f.Field)
+					if f.Field == "value" && value == nil {
+						value = f.New
+						assert.Equal(t, tt.expectedValue == "", f.Redacted)
+					}
+				}
+				changes = append(changes, fmt.Sprintf("%s %s", change.Action, strings.Join(fields, ",")))
+
+				if change.Action == planFailed {
+					assert.NotNil(t, change.Error)
+				}
+			}
+			assert.Equal(t, tt.expectedChanges, changes)
+			if tt.expectedValue != "" {
+				assert.Equal(t, tt.expectedValue, value)
+			}
+		})
+	}
+}
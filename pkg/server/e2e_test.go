@@ -0,0 +1,113 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwardentest"
+)
+
+// TestEndToEnd runs requests through the Warden and the Bitwarden SDK against a local stand-in for
+// the Bitwarden servers.
+func TestEndToEnd(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "login per request", config: Config{}},
+		{name: "session reuse", config: Config{SessionTTL: time.Minute, SessionMaxSize: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bw := bitwardentest.NewServer(t)
+			tt.config.StateDir = t.TempDir()
+			s := NewServer(tt.config)
+			if s.sessions != nil {
+				defer s.sessions.Close()
+			}
+			testEndToEnd(t, bw, s.routes())
+			assert.Contains(t, bw.Requests(), "POST /identity/connect/token")
+		})
+	}
+}
+
+func testEndToEnd(t *testing.T, bw *bitwardentest.Server, routes http.Handler) {
+	t.Helper()
+
+	accessToken := bw.NewAccessToken()
+	do := func(token, method, path, body string, response any) int {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set(bitwarden.WardenHeaderAccessToken, token)
+		req.Header.Set(bitwarden.WardenHeaderAPIURL, bw.APIURL())
+		req.Header.Set(bitwarden.WardenHeaderIdentityURL, bw.IdentityURL())
+		req.Header.Set(bitwarden.WardenHeaderStatePath, "state")
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, req)
+		if response != nil {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), response), w.Body.String())
+		}
+
+		return w.Code
+	}
+
+	// Well-formed, but not registered with the server.
+	const unknownAccessToken = "0.ec2c1d46-6a4b-4751-a310-af9601317f2d.unknown:AAAAAAAAAAAAAAAAAAAAAA=="
+
+	projectID := bw.AddProject("project")
+	secretID := bw.AddSecret("key", "value", "note", projectID)
+
+	got := &sdk.SecretResponse{}
+	require.Equal(t, http.StatusOK, do(accessToken, http.MethodGet, api+"/secret", `{"id": "`+secretID+`"}`, got))
+	assert.Equal(t, "value", got.Value)
+	assert.Equal(t, "note", got.Note)
+
+	got = &sdk.SecretResponse{}
+	require.Equal(t, http.StatusOK, do(accessToken, http.MethodGet, apiV2+"/secrets/"+secretID, "", got))
+	assert.Equal(t, "key", got.Key)
+
+	list := &sdk.SecretIdentifiersResponse{}
+	require.Equal(t, http.StatusOK, do(accessToken, http.MethodGet, apiV2+"/organizations/"+bw.OrganizationID+"/secrets", "", list))
+	assert.Len(t, list.Data, 1)
+
+	created := &sdk.SecretResponse{}
+	require.Equal(t, http.StatusOK, do(accessToken, http.MethodPost, apiV2+"/secrets",
+		`{"organizationId": "`+bw.OrganizationID+`", "key": "created", "value": "created value", "projectIds": ["`+projectID+`"]}`, created))
+	key, value, _, ok := bw.Secret(created.ID)
+	require.True(t, ok)
+	assert.Equal(t, []string{"created", "created value"}, []string{key, value})
+
+	require.Equal(t, http.StatusOK, do(accessToken, http.MethodDelete, apiV2+"/secrets/"+created.ID, "", nil))
+
+	notFound := &apierror.Body{}
+	assert.Equal(t, http.StatusNotFound, do(accessToken, http.MethodGet, apiV2+"/secrets/"+created.ID, "", notFound))
+	assert.Equal(t, apierror.CodeNotFound, notFound.Code)
+
+	unauthorized := &apierror.Body{}
+	assert.Equal(t, http.StatusUnauthorized, do(unknownAccessToken, http.MethodGet, apiV2+"/secrets/"+secretID, "", unauthorized))
+	assert.Equal(t, apierror.CodeUnauthorized, unauthorized.Code)
+}
@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+// secretETag returns the entity tag of a secret. Bitwarden sets the revision date on every change
+// of a secret, so it identifies the version of the secret.
+func secretETag(secret *sdk.SecretResponse) string {
+	return `"` + sortableTime(secret.RevisionDate) + `"`
+}
+
+// etagMatches reports whether the entity tag is listed in an If-Match or If-None-Match header,
+// `*` matches any tag. Weak comparison, used for If-None-Match, ignores the `W/` prefix.
+func etagMatches(header, etag string, weak bool) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkIfMatch fails with 412 Precondition Failed if the request has an If-Match header that
+// doesn't match the current version of the secret. The secret is fetched from Bitwarden, not
+// from the cache. Bitwarden can't update conditionally, so a change made between the check and
+// the update of the caller isn't detected.
+func checkIfMatch(r *http.Request, c sdk.BitwardenClientInterface, ids []string) error {
+	ifMatch := ifMatchHeader(r)
+	if ifMatch == "" {
+		return nil
+	}
+
+	if len(ids) != 1 {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "If-Match is only supported for a single secret, got %d", len(ids))
+	}
+
+	secret, err := c.Secrets().Get(ids[0])
+	if err != nil {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	return checkETag(ifMatch, secret)
+}
+
+// checkETag fails with 412 Precondition Failed if the If-Match header is set and doesn't match
+// the secret.
+func checkETag(ifMatch string, secret *sdk.SecretResponse) error {
+	if ifMatch == "" {
+		return nil
+	}
+
+	if etag := secretETag(secret); !etagMatches(ifMatch, etag, false) {
+		return apierror.Errorf(http.StatusPreconditionFailed, apierror.CodePreconditionFailed, "secret %s was modified, its current ETag is %s", secret.ID, etag)
+	}
+
+	return nil
+}
+
+// ifMatchHeader returns the If-Match header of the request, joining repeated headers.
+func ifMatchHeader(r *http.Request) string {
+	return strings.Join(r.Header.Values("If-Match"), ",")
+}
+
+// respondSecret writes the secret along with its ETag. GET requests with an If-None-Match header
+// matching the ETag get 304 Not Modified instead.
+func (s *Server) respondSecret(w http.ResponseWriter, r *http.Request, secret *sdk.SecretResponse, err error) {
+	if err == nil && secret != nil {
+		etag := secretETag(secret)
+		w.Header().Set("ETag", etag)
+
+		ifNoneMatch := strings.Join(r.Header.Values("If-None-Match"), ",")
+		if r.Method == http.MethodGet && ifNoneMatch != "" && etagMatches(ifNoneMatch, etag, true) {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+	}
+
+	s.respond(w, r, secret, err)
+}
@@ -0,0 +1,100 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+func TestSecretETags(t *testing.T) {
+	client := newMemoryClient(t)
+	secret, err := client.Secrets().Create("key", "value", "", "org-1", nil)
+	require.NoError(t, err)
+	other, err := client.Secrets().Create("other", "value", "", "org-1", nil)
+	require.NoError(t, err)
+	etag := secretETag(secret)
+	s := NewServer(Config{})
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		header         string
+		value          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "get", method: http.MethodGet, path: apiV2 + "/secrets/" + secret.ID, expectedStatus: http.StatusOK},
+		{name: "get not modified", method: http.MethodGet, path: apiV2 + "/secrets/" + secret.ID, header: "If-None-Match", value: `"other", ` + etag, expectedStatus: http.StatusNotModified},
+		{name: "get weak not modified", method: http.MethodGet, path: api + "/secret", body: `{"id": "` + secret.ID + `"}`, header: "If-None-Match", value: "W/" + etag, expectedStatus: http.StatusNotModified},
+		{name: "get any not modified", method: http.MethodGet, path: apiV2 + "/organizations/org-1/secrets/by-key?key=key", header: "If-None-Match", value: "*", expectedStatus: http.StatusNotModified},
+		{name: "get modified", method: http.MethodGet, path: apiV2 + "/secrets/" + secret.ID, header: "If-None-Match", value: `"other"`, expectedStatus: http.StatusOK},
+		{name: "update mismatch", method: http.MethodPut, path: apiV2 + "/secrets/" + secret.ID, body: `{"organizationId": "org-1", "key": "key", "value": "changed"}`, header: "If-Match", value: `"other"`, expectedStatus: http.StatusPreconditionFailed, expectedCode: apierror.CodePreconditionFailed},
+		{name: "update weak mismatch", method: http.MethodPut, path: api + "/secret", body: `{"id": "` + secret.ID + `", "organizationId": "org-1", "key": "key", "value": "changed"}`, header: "If-Match", value: "W/" + etag, expectedStatus: http.StatusPreconditionFailed, expectedCode: apierror.CodePreconditionFailed},
+		{name: "update match", method: http.MethodPut, path: apiV2 + "/secrets/" + secret.ID, body: `{"organizationId": "org-1", "key": "key", "value": "changed"}`, header: "If-Match", value: etag, expectedStatus: http.StatusOK},
+		{name: "update stale", method: http.MethodPut, path: api + "/secret", body: `{"id": "` + secret.ID + `", "organizationId": "org-1", "key": "key", "value": "changed again"}`, header: "If-Match", value: etag, expectedStatus: http.StatusPreconditionFailed, expectedCode: apierror.CodePreconditionFailed},
+		{name: "update unknown", method: http.MethodPut, path: apiV2 + "/secrets/unknown", body: `{"organizationId": "org-1", "key": "key"}`, header: "If-Match", value: "*", expectedStatus: http.StatusNotFound, expectedCode: apierror.CodeNotFound},
+		{name: "delete several", method: http.MethodDelete, path: apiV2 + "/secrets?ids=" + secret.ID + "," + other.ID, header: "If-Match", value: "*", expectedStatus: http.StatusBadRequest, expectedCode: apierror.CodeInvalidRequest},
+		{name: "delete mismatch", method: http.MethodDelete, path: api + "/secret", body: `{"ids": ["` + other.ID + `"]}`, header: "If-Match", value: etag, expectedStatus: http.StatusPreconditionFailed, expectedCode: apierror.CodePreconditionFailed},
+		{name: "delete match", method: http.MethodDelete, path: apiV2 + "/secrets/" + other.ID, header: "If-Match", value: secretETag(other), expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			w := serveRequestWithClient(s, client, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedCode != "" {
+				var body apierror.Body
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, tt.expectedCode, body.Code)
+
+				return
+			}
+
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+				assert.Empty(t, w.Body.String())
+
+				return
+			}
+
+			if tt.method != http.MethodDelete {
+				current, err := client.Secrets().Get(secret.ID)
+				require.NoError(t, err)
+				assert.Equal(t, secretETag(current), w.Header().Get("ETag"))
+			}
+		})
+	}
+
+	stored, err := client.Secrets().Get(secret.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "changed", stored.Value)
+}
@@ -0,0 +1,394 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+// Formats secrets can be exported in.
+const (
+	exportDotenv     = "dotenv"
+	exportYAML       = "yaml"
+	exportJSON       = "json"
+	exportKubernetes = "kubernetes"
+)
+
+const contentTypeYAML = "application/yaml"
+
+// exportContentTypes are the content types the formats are served with.
+var exportContentTypes = map[string]string{
+	exportDotenv:     contentTypeText + "; charset=utf-8",
+	exportYAML:       contentTypeYAML,
+	exportJSON:       contentTypeJSON,
+	exportKubernetes: contentTypeYAML,
+}
+
+// exportMediaTypes maps the media types of an Accept header to the format served for them. The
+// Kubernetes manifest can only be selected with the format parameter.
+var exportMediaTypes = map[string]string{
+	"*/*":                exportJSON,
+	"application/*":      exportJSON,
+	"application/json":   exportJSON,
+	"application/yaml":   exportYAML,
+	"application/x-yaml": exportYAML,
+	"text/yaml":          exportYAML,
+	"text/*":             exportDotenv,
+	"text/plain":         exportDotenv,
+}
+
+var (
+	// dotenvKeyPattern matches keys that can be used as variable names in dotenv files.
+	dotenvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// kubernetesKeyPattern matches keys allowed in the data of a Kubernetes Secret.
+	kubernetesKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]{1,253}$`)
+	// kubernetesNamePattern matches DNS subdomains, used as names of Kubernetes objects.
+	kubernetesNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+	// kubernetesNamespacePattern matches DNS labels, used as names of Kubernetes namespaces.
+	kubernetesNamespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+)
+
+// ExportSecretsRequest exports the values of secrets by their key. Secrets are selected by their
+// IDs, or are those of an organization, optionally restricted to some projects.
+type ExportSecretsRequest struct {
+	OrganizationID string   `json:"organizationId,omitempty"`
+	ProjectIDs     []string `json:"projectIds,omitempty"`
+	IDs            []string `json:"ids,omitempty"`
+	// Format is dotenv, yaml, json or kubernetes. If empty, it's taken from the format query
+	// parameter or the Accept header.
+	Format string `json:"format,omitempty"`
+	// Name and Namespace are the metadata of the Kubernetes Secret manifest. Name is required for
+	// the kubernetes format.
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (r *ExportSecretsRequest) validate() error {
+	if len(r.IDs) > 0 && (r.OrganizationID != "" || len(r.ProjectIDs) > 0) {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "ids can't be combined with organizationId or projectIds")
+	}
+
+	if len(r.IDs) == 0 && r.OrganizationID == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing organizationId or ids")
+	}
+
+	if _, ok := exportContentTypes[r.Format]; !ok {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "unknown format %q, supported are dotenv, yaml, json and kubernetes", r.Format)
+	}
+
+	if r.Format != exportKubernetes {
+		return nil
+	}
+
+	if !kubernetesNamePattern.MatchString(r.Name) {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid name %q, the kubernetes format requires the name of the Secret", r.Name)
+	}
+
+	if r.Namespace != "" && !kubernetesNamespacePattern.MatchString(r.Namespace) {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid namespace %q", r.Namespace)
+	}
+
+	return nil
+}
+
+// ExportSecrets returns the values of the selected secrets by their key. Bitwarden doesn't enforce
+// unique keys, several secrets with the same key are a conflict.
+func (svc *service) ExportSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *ExportSecretsRequest) (map[string]string, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID, e.ProjectIDs, e.SecretIDs = "secrets.export", request.OrganizationID, request.ProjectIDs, request.IDs
+	})
+
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	ids := request.IDs
+	if len(ids) == 0 {
+		match, err := (&SecretsFilter{ProjectIDs: request.ProjectIDs}).matcher()
+		if err != nil {
+			return nil, err
+		}
+
+		identifiers, err := c.Secrets().List(request.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+
+		for _, identifier := range identifiers.Data {
+			if match(identifier) {
+				ids = append(ids, identifier.ID)
+			}
+		}
+
+		audit.Annotate(ctx, func(e *audit.Event) {
+			e.SecretIDs = ids
+		})
+	}
+
+	values := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return values, nil
+	}
+
+	secrets, err := svc.getSecretsByIDs(ctx, c, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secrets: %w", err)
+	}
+
+	owners := make(map[string][]string, len(secrets.Data))
+	for _, secret := range secrets.Data {
+		owners[secret.Key] = append(owners[secret.Key], secret.ID)
+		values[secret.Key] = secret.Value
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(owners)) {
+		if len(owners[key]) > 1 {
+			return nil, apierror.Errorf(http.StatusConflict, apierror.CodeConflict, "%d secrets with key %q selected: %s", len(owners[key]), key, strings.Join(owners[key], ", "))
+		}
+	}
+
+	return values, nil
+}
+
+// renderExport renders the values in the format of the request.
+func renderExport(request *ExportSecretsRequest, values map[string]string) ([]byte, error) {
+	switch request.Format {
+	case exportDotenv:
+		return renderDotenv(values)
+	case exportYAML:
+		return marshalYAML(values)
+	case exportKubernetes:
+		return renderKubernetesSecret(request, values)
+	}
+
+	return json.Marshal(values)
+}
+
+// renderDotenv renders one KEY=value line per secret, sorted by key. Values are single quoted,
+// which dotenv parsers read literally, unless they contain single quotes or line breaks. Those are
+// double quoted, escaping backslashes, double quotes, dollar signs and line breaks.
+func renderDotenv(values map[string]string) ([]byte, error) {
+	if err := checkKeys(values, dotenvKeyPattern, exportDotenv); err != nil {
+		return nil, err
+	}
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+
+	var b bytes.Buffer
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value := values[key]
+		if strings.ContainsAny(value, "'\n\r") {
+			fmt.Fprintf(&b, "%s=\"%s\"\n", key, escape.Replace(value))
+		} else {
+			fmt.Fprintf(&b, "%s='%s'\n", key, value)
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+type kubernetesSecret struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   kubernetesMetadata `yaml:"metadata"`
+	Type       string             `yaml:"type"`
+	Data       map[string]string  `yaml:"data"`
+}
+
+type kubernetesMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// renderKubernetesSecret renders a manifest of an Opaque v1/Secret with the base64 encoded values
+// as data.
+func renderKubernetesSecret(request *ExportSecretsRequest, values map[string]string) ([]byte, error) {
+	if err := checkKeys(values, kubernetesKeyPattern, exportKubernetes); err != nil {
+		return nil, err
+	}
+
+	secret := kubernetesSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   kubernetesMetadata{Name: request.Name, Namespace: request.Namespace},
+		Type:       "Opaque",
+		Data:       make(map[string]string, len(values)),
+	}
+	for key, value := range values {
+		secret.Data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	return marshalYAML(secret)
+}
+
+// marshalYAML encodes v indented by two spaces, like Kubernetes manifests usually are.
+func marshalYAML(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// checkKeys fails if any key can't be represented in the format.
+func checkKeys(values map[string]string, pattern *regexp.Regexp, format string) error {
+	var invalid []string
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !pattern.MatchString(key) {
+			invalid = append(invalid, fmt.Sprintf("%q", key))
+		}
+	}
+
+	if len(invalid) > 0 {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "keys %s can't be exported as %s", strings.Join(invalid, ", "), format)
+	}
+
+	return nil
+}
+
+// negotiateExportFormat sets the format of the request, if not set yet, to the format query
+// parameter or the media type of the Accept header with the highest weight that can be served,
+// the first one on equal weights. Types weighted q=0 are refused. Without either, the format
+// is json.
+func negotiateExportFormat(r *http.Request, request *ExportSecretsRequest) error {
+	if request.Format == "" {
+		request.Format = r.URL.Query().Get("format")
+	}
+	if request.Format != "" {
+		return nil
+	}
+
+	accept := strings.Join(r.Header.Values("Accept"), ",")
+	if strings.TrimSpace(accept) == "" {
+		request.Format = exportJSON
+
+		return nil
+	}
+
+	best := 0.0
+	for value := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(value)
+		if err != nil {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := params["q"]; ok {
+			weight, err = strconv.ParseFloat(q, 64)
+			if err != nil || weight < 0 || weight > 1 {
+				continue
+			}
+		}
+
+		if format, ok := exportMediaTypes[mediaType]; ok && weight > best {
+			request.Format = format
+			best = weight
+		}
+	}
+	if request.Format != "" {
+		return nil
+	}
+
+	return apierror.Errorf(http.StatusNotAcceptable, apierror.CodeNotAcceptable, "none of %q can be served, use dotenv (text/plain), yaml (application/yaml) or json (application/json)", accept)
+}
+
+// respondExport writes the values rendered in the format of the request.
+func (s *Server) respondExport(w http.ResponseWriter, r *http.Request, request *ExportSecretsRequest, values map[string]string, err error) {
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	body, err := renderExport(request, values)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentTypes[request.Format])
+	w.Header().Set("Vary", "Accept")
+	_, _ = w.Write(body)
+}
+
+func (s *Server) exportSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	request := &ExportSecretsRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := negotiateExportFormat(r, request); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	values, err := s.svc.ExportSecrets(r.Context(), c, request)
+	s.respondExport(w, r, request, values, err)
+}
+
+func (s *Server) exportSecretsV2Handler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &ExportSecretsRequest{
+		OrganizationID: query.Get("organizationId"),
+		ProjectIDs:     queryValues(query, "projectId"),
+		IDs:            queryValues(query, "ids"),
+		Name:           query.Get("name"),
+		Namespace:      query.Get("namespace"),
+	}
+	if err := negotiateExportFormat(r, request); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	values, err := s.svc.ExportSecrets(r.Context(), c, request)
+	s.respondExport(w, r, request, values, err)
+}
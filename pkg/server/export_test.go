@@ -0,0 +1,222 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+func TestExportSecrets(t *testing.T) {
+	tests := []struct {
+		name                string
+		path                string
+		body                string
+		accept              string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+		expectedError       string
+	}{
+		{
+			name:                "json by default",
+			path:                apiV2 + "/export?organizationId=org-1",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeJSON,
+			expectedBody:        `{"DB_PASSWORD":"pa'ss $HOME \"x\"\nnext","DB_USER":"user"}`,
+		},
+		{
+			name:                "dotenv",
+			path:                apiV2 + "/export?organizationId=org-1&format=dotenv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "DB_PASSWORD=\"pa'ss \\$HOME \\\"x\\\"\\nnext\"\nDB_USER='user'\n",
+		},
+		{
+			name:                "yaml by accept header",
+			path:                apiV2 + "/export?projectId={project}&organizationId=org-1",
+			accept:              "text/html, application/yaml;q=0.9",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeYAML,
+			expectedBody:        "DB_USER: user\n",
+		},
+		{
+			name:                "dotenv by accept header",
+			path:                apiV2 + "/export?ids={user}",
+			accept:              "application/json;q=0, text/plain",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "DB_USER='user'\n",
+		},
+		{
+			name:                "highest weight by accept header",
+			path:                apiV2 + "/export?ids={user}",
+			accept:              "text/plain;q=0.1, application/yaml;q=0.5, application/json;q=0.8",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeJSON,
+			expectedBody:        `{"DB_USER":"user"}`,
+		},
+		{
+			name:                "first of equal weights by accept header",
+			path:                apiV2 + "/export?ids={user}",
+			accept:              "application/json;q=0.5, application/yaml;q=0.500",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeJSON,
+			expectedBody:        `{"DB_USER":"user"}`,
+		},
+		{
+			name:                "kubernetes",
+			path:                apiV2 + "/export?ids={user}&format=kubernetes&name=db&namespace=app",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeYAML,
+			expectedBody: `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+  namespace: app
+type: Opaque
+data:
+  DB_USER: dXNlcg==
+`,
+		},
+		{
+			name:                "v1",
+			path:                api + "/export",
+			body:                `{"organizationId": "org-1", "projectIds": ["{project}"], "format": "dotenv"}`,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "DB_USER='user'\n",
+		},
+		{
+			name:                "empty",
+			path:                apiV2 + "/export?organizationId=org-2",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeJSON,
+			expectedBody:        `{}`,
+		},
+		{
+			name:           "duplicate keys",
+			path:           apiV2 + "/export?organizationId=org-3",
+			expectedStatus: http.StatusConflict,
+			expectedError:  `2 secrets with key "key" selected`,
+		},
+		{
+			name:           "invalid dotenv key",
+			path:           apiV2 + "/export?organizationId=org-4&format=dotenv",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `keys "db password" can't be exported as dotenv`,
+		},
+		{
+			name:           "dotenv key with dash",
+			path:           apiV2 + "/export?organizationId=org-5&format=dotenv",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `keys "DB-PASS" can't be exported as dotenv`,
+		},
+		{
+			name:                "yaml key with dash",
+			path:                apiV2 + "/export?organizationId=org-5&format=yaml",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: contentTypeYAML,
+			expectedBody:        "DB-PASS: value\n",
+		},
+		{
+			name:           "kubernetes without name",
+			path:           apiV2 + "/export?organizationId=org-1&format=kubernetes",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "requires the name of the Secret",
+		},
+		{
+			name:           "unknown format",
+			path:           apiV2 + "/export?organizationId=org-1&format=toml",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `unknown format "toml"`,
+		},
+		{
+			name:           "not acceptable",
+			path:           apiV2 + "/export?organizationId=org-1",
+			accept:         "text/html",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedError:  `none of "text/html" can be served`,
+		},
+		{
+			name:           "not acceptable by weight",
+			path:           apiV2 + "/export?organizationId=org-1",
+			accept:         "application/json;q=0.0, text/*;q=0.000, */*;q=0",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedError:  "can be served",
+		},
+		{
+			name:           "ids and organization",
+			path:           apiV2 + "/export?organizationId=org-1&ids={user}",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "ids can't be combined",
+		},
+		{
+			name:           "missing selection",
+			path:           apiV2 + "/export",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "missing organizationId or ids",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient(t)
+			project, err := client.Projects().Create("org-1", "project")
+			require.NoError(t, err)
+			user, err := client.Secrets().Create("DB_USER", "user", "", "org-1", []string{project.ID})
+			require.NoError(t, err)
+			_, err = client.Secrets().Create("DB_PASSWORD", "pa'ss $HOME \"x\"\nnext", "", "org-1", nil)
+			require.NoError(t, err)
+			for range 2 {
+				_, err = client.Secrets().Create("key", "value", "", "org-3", nil)
+				require.NoError(t, err)
+			}
+			_, err = client.Secrets().Create("db password", "value", "", "org-4", nil)
+			require.NoError(t, err)
+			_, err = client.Secrets().Create("DB-PASS", "value", "", "org-5", nil)
+			require.NoError(t, err)
+
+			replacer := strings.NewReplacer("{user}", user.ID, "{project}", project.ID)
+			req := httptest.NewRequest(http.MethodGet, replacer.Replace(tt.path), bytes.NewBufferString(replacer.Replace(tt.body)))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			w := serveRequestWithClient(NewServer(Config{}), client, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedError != "" {
+				var body apierror.Body
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Contains(t, body.Message, tt.expectedError)
+
+				return
+			}
+
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+// ListSecretsRequest lists the secrets of an organization, optionally filtered and paginated.
+// Without filters and limit every secret of the organization is returned, like
+// sdk.SecretIdentifiersRequest does.
+type ListSecretsRequest struct {
+	OrganizationID string `json:"organizationId"`
+
+	SecretsFilter
+	Page
+}
+
+// SecretsFilter selects secrets by their key and projects. Secrets have to match every filter
+// that is set.
+type SecretsFilter struct {
+	// KeyPrefix matches keys starting with the prefix.
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+	// KeyPattern matches keys against a glob pattern, like `db-*`, see path.Match.
+	KeyPattern string `json:"keyPattern,omitempty"`
+	// KeyRegex matches keys against a regular expression. It's unanchored, use `^` and `$` to
+	// match whole keys.
+	KeyRegex string `json:"keyRegex,omitempty"`
+	// ProjectIDs matches secrets in any of the projects.
+	ProjectIDs []string `json:"projectIds,omitempty"`
+	// WithValues also returns the matching secrets including their values.
+	WithValues bool `json:"withValues,omitempty"`
+}
+
+// SecretsListResponse contains the identifiers of the listed secrets. Secrets is only set if
+// values were requested.
+type SecretsListResponse struct {
+	Data    []sdk.SecretIdentifierResponse `json:"data"`
+	Secrets []sdk.SecretResponse           `json:"secrets,omitempty"`
+	// ContinuationToken is set if there are more secrets, pass it to get the next page.
+	ContinuationToken string `json:"continuationToken,omitempty"`
+}
+
+// matcher returns a function reporting whether a secret matches the filter.
+func (f *SecretsFilter) matcher() (func(sdk.SecretIdentifierResponse) bool, error) {
+	if f.KeyPattern != "" {
+		if _, err := path.Match(f.KeyPattern, ""); err != nil {
+			return nil, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid keyPattern: %w", err)
+		}
+	}
+
+	var re *regexp.Regexp
+	if f.KeyRegex != "" {
+		var err error
+		if re, err = regexp.Compile(f.KeyRegex); err != nil {
+			return nil, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid keyRegex: %w", err)
+		}
+	}
+
+	return func(secret sdk.SecretIdentifierResponse) bool {
+		if !strings.HasPrefix(secret.Key, f.KeyPrefix) {
+			return false
+		}
+
+		if f.KeyPattern != "" {
+			if ok, _ := path.Match(f.KeyPattern, secret.Key); !ok {
+				return false
+			}
+		}
+
+		if re != nil && !re.MatchString(secret.Key) {
+			return false
+		}
+
+		if len(f.ProjectIDs) > 0 && !slices.ContainsFunc(secret.ProjectIDS, func(id string) bool {
+			return slices.Contains(f.ProjectIDs, id)
+		}) {
+			return false
+		}
+
+		return true
+	}, nil
+}
+
+// queryFilter returns the filter set by the query parameters of the request.
+func queryFilter(query url.Values) (SecretsFilter, error) {
+	filter := SecretsFilter{
+		KeyPrefix:  query.Get("keyPrefix"),
+		KeyPattern: query.Get("keyPattern"),
+		KeyRegex:   query.Get("keyRegex"),
+		ProjectIDs: queryValues(query, "projectId"),
+	}
+
+	if v := query.Get("withValues"); v != "" {
+		withValues, err := strconv.ParseBool(v)
+		if err != nil {
+			return SecretsFilter{}, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid withValues: %w", err)
+		}
+		filter.WithValues = withValues
+	}
+
+	return filter, nil
+}
+
+// queryValues returns the values of a query parameter, which are either comma separated, like
+// `?ids=a,b`, or passed as repeated parameters, like `?ids=a&ids=b`.
+func queryValues(query url.Values, name string) []string {
+	var values []string
+	for _, v := range query[name] {
+		for value := range strings.SplitSeq(v, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+func secretIDs(secrets []sdk.SecretIdentifierResponse) []string {
+	ids := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		ids = append(ids, secret.ID)
+	}
+
+	return ids
+}
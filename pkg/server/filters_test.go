@@ -0,0 +1,90 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSecretsFilter(t *testing.T) {
+	client := newMemoryClient(t)
+	backend, err := client.Projects().Create("org-1", "backend")
+	require.NoError(t, err)
+	frontend, err := client.Projects().Create("org-1", "frontend")
+	require.NoError(t, err)
+	for key, projectID := range map[string]string{
+		"db-password":  backend.ID,
+		"db-user":      backend.ID,
+		"api-token":    backend.ID,
+		"cdn-token":    frontend.ID,
+		"cdn-password": frontend.ID,
+	} {
+		_, err := client.Secrets().Create(key, key+"-value", "", "org-1", []string{projectID})
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		expectedKeys   []string
+		expectedValues []string
+	}{
+		{name: "v1 without filter", path: api + "/secrets", body: `{"organizationId": "org-1"}`, expectedStatus: http.StatusOK, expectedKeys: []string{"api-token", "cdn-password", "cdn-token", "db-password", "db-user"}},
+		{name: "v1 key prefix", path: api + "/secrets", body: `{"organizationId": "org-1", "keyPrefix": "db-"}`, expectedStatus: http.StatusOK, expectedKeys: []string{"db-password", "db-user"}},
+		{name: "v1 with values", path: api + "/secrets", body: `{"organizationId": "org-1", "keyPrefix": "db-", "withValues": true}`, expectedStatus: http.StatusOK, expectedKeys: []string{"db-password", "db-user"}, expectedValues: []string{"db-password-value", "db-user-value"}},
+		{name: "v2 without filter", path: apiV2 + "/organizations/org-1/secrets", expectedStatus: http.StatusOK, expectedKeys: []string{"api-token", "cdn-password", "cdn-token", "db-password", "db-user"}},
+		{name: "v2 key pattern", path: apiV2 + "/organizations/org-1/secrets?keyPattern=*-token", expectedStatus: http.StatusOK, expectedKeys: []string{"api-token", "cdn-token"}},
+		{name: "v2 key regex", path: apiV2 + "/organizations/org-1/secrets?keyRegex=^(api|db)-", expectedStatus: http.StatusOK, expectedKeys: []string{"api-token", "db-password", "db-user"}},
+		{name: "v2 project", path: apiV2 + "/organizations/org-1/secrets?projectId=" + frontend.ID, expectedStatus: http.StatusOK, expectedKeys: []string{"cdn-password", "cdn-token"}},
+		{name: "v2 projects", path: apiV2 + "/organizations/org-1/secrets?projectId=" + frontend.ID + "," + backend.ID + "&keyPattern=*-password", expectedStatus: http.StatusOK, expectedKeys: []string{"cdn-password", "db-password"}},
+		{name: "v2 with values", path: apiV2 + "/organizations/org-1/secrets?keyPrefix=cdn-&keyRegex=token&withValues=true", expectedStatus: http.StatusOK, expectedKeys: []string{"cdn-token"}, expectedValues: []string{"cdn-token-value"}},
+		{name: "v2 without matches", path: apiV2 + "/organizations/org-1/secrets?keyPrefix=unknown&withValues=true", expectedStatus: http.StatusOK, expectedKeys: []string{}},
+		{name: "invalid key pattern", path: apiV2 + "/organizations/org-1/secrets?keyPattern=[", expectedStatus: http.StatusBadRequest},
+		{name: "invalid key regex", path: apiV2 + "/organizations/org-1/secrets?keyRegex=(", expectedStatus: http.StatusBadRequest},
+		{name: "invalid with values", path: apiV2 + "/organizations/org-1/secrets?withValues=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClient(NewServer(Config{}), client, http.MethodGet, tt.path, tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response SecretsListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			keys := []string{}
+			for _, secret := range response.Data {
+				keys = append(keys, secret.Key)
+			}
+			assert.ElementsMatch(t, tt.expectedKeys, keys)
+
+			var values []string
+			for _, secret := range response.Secrets {
+				values = append(values, secret.Value)
+			}
+			assert.ElementsMatch(t, tt.expectedValues, values)
+		})
+	}
+}
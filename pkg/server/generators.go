@@ -0,0 +1,41 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+// PasswordResponse contains a password generated by the Bitwarden generator.
+type PasswordResponse struct {
+	Password string `json:"password"`
+}
+
+func (s *Server) generatePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.PasswordGeneratorRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GeneratePassword(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
@@ -0,0 +1,112 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+type mockGenerators struct {
+	password *string
+	err      error
+
+	request sdk.PasswordGeneratorRequest
+}
+
+var _ sdk.GeneratorsInterface = &mockGenerators{}
+
+func (m *mockGenerators) GeneratePassword(request sdk.PasswordGeneratorRequest) (*string, error) {
+	m.request = request
+
+	return m.password, m.err
+}
+
+func TestGeneratePasswordHandler(t *testing.T) {
+	password := "s3cr3t-P@ss"
+	minNumber := int64(2)
+
+	tests := []struct {
+		name            string
+		body            string
+		generators      *mockGenerators
+		expectedStatus  int
+		expectedBody    string
+		expectedRequest sdk.PasswordGeneratorRequest
+	}{
+		{
+			name:           "success",
+			body:           `{"length": 24, "lowercase": true, "uppercase": true, "numbers": true, "special": false, "avoidAmbiguous": true, "minNumber": 2}`,
+			generators:     &mockGenerators{password: &password},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"password":"s3cr3t-P@ss"}`,
+			expectedRequest: sdk.PasswordGeneratorRequest{
+				Length:         24,
+				Lowercase:      true,
+				Uppercase:      true,
+				Numbers:        true,
+				AvoidAmbiguous: true,
+				MinNumber:      &minNumber,
+			},
+		},
+		{
+			name:           "generator error",
+			body:           `{"length": 4, "lowercase": true}`,
+			generators:     &mockGenerators{err: errors.New("length too short")},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": "bad_request", "message": "failed to generate password: length too short", "retryable": false}`,
+			expectedRequest: sdk.PasswordGeneratorRequest{
+				Length:    4,
+				Lowercase: true,
+			},
+		},
+		{
+			name:           "empty response",
+			body:           `{"length": 16, "lowercase": true}`,
+			generators:     &mockGenerators{},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"code": "internal", "message": "failed to generate password: empty response", "retryable": false}`,
+			expectedRequest: sdk.PasswordGeneratorRequest{
+				Length:    16,
+				Lowercase: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(Config{})
+			req := httptest.NewRequest(http.MethodPost, "/generators/password", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), bitwarden.ContextClientKey, &mockClient{generators: tt.generators})
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			s.generatePasswordHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			assert.Equal(t, tt.expectedRequest, tt.generators.request)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	bitwardenv1 "github.com/external-secrets/bitwarden-sdk-server/pkg/api/bitwarden/v1"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/metrics"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/tracing"
+)
+
+// metadataRequestID is the metadata key callers pass a request ID in, reported in audit events.
+const metadataRequestID = "x-request-id"
+
+// newGRPCServer returns the gRPC server of the API. Calls authenticate with the metadata
+// equivalents of the Warden headers and are handled by the same service as the REST API.
+func (s *Server) newGRPCServer() (*grpc.Server, error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor, metrics.UnaryServerInterceptor, statusInterceptor, s.auditInterceptor, s.wardenInterceptor),
+	}
+	if !s.Insecure {
+		creds, err := credentials.NewServerTLSFromFile(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load grpc tls credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	srv := grpc.NewServer(opts...)
+	bitwardenv1.RegisterSecretsServiceServer(srv, &grpcSecrets{svc: s.svc})
+	bitwardenv1.RegisterProjectsServiceServer(srv, &grpcProjects{svc: s.svc})
+	if s.GRPCReflection {
+		reflection.Register(srv)
+	}
+
+	return srv, nil
+}
+
+// serveGRPC starts serving the gRPC API in the background.
+func (s *Server) serveGRPC() error {
+	srv, err := s.newGRPCServer()
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", s.GRPCAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on grpc address: %w", err)
+	}
+
+	s.grpc = srv
+	go func() {
+		slog.Info("starting grpc listener", "addr", s.GRPCAddr, "insecure", s.Insecure)
+		if err := srv.Serve(lis); err != nil {
+			slog.Error("grpc listener stopped unexpectedly", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// statusInterceptor converts errors of calls to gRPC status errors with the code matching the http
+// status the REST API reports for them.
+func statusInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return resp, nil
+}
+
+// auditInterceptor records an audit event for every call, like audit.Middleware does for http
+// requests.
+func (s *Server) auditInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.auditLog == nil {
+		return handler(ctx, req)
+	}
+
+	e := &audit.Event{
+		Caller:    bitwarden.Fingerprint(metadataValue(ctx, bitwarden.WardenHeaderAccessToken)),
+		RequestID: metadataValue(ctx, metadataRequestID),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		e.ClientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(e.ClientIP); err == nil {
+			e.ClientIP = host
+		}
+	}
+
+	resp, err := handler(audit.WithEvent(ctx, e), req)
+
+	if e.Operation == "" {
+		e.Operation = info.FullMethod
+	}
+
+	code := http.StatusOK
+	if err != nil {
+		code, _ = apierror.Classify(err)
+	}
+	s.auditLog.Record(e, code)
+
+	return resp, err
+}
+
+// wardenInterceptor authenticates every call using the metadata equivalents of the Warden headers.
+func (s *Server) wardenInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, done, err := s.warden.Authenticate(ctx, &bitwarden.LoginRequest{
+		RequestBase: &bitwarden.RequestBase{
+			APIURL:      metadataValue(ctx, bitwarden.WardenHeaderAPIURL),
+			IdentityURL: metadataValue(ctx, bitwarden.WardenHeaderIdentityURL),
+		},
+		AccessToken: metadataValue(ctx, bitwarden.WardenHeaderAccessToken),
+		StatePath:   metadataValue(ctx, bitwarden.WardenHeaderStatePath),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	return handler(ctx, req)
+}
+
+// metadataValue returns the first value of the incoming metadata key. The key is the name of
+// the http header, metadata keys are lower case.
+func metadataValue(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(key))
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// grpcError converts an error to a gRPC status error. Errors that already are status errors are
+// kept.
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code, _ := apierror.Classify(err)
+
+	return status.Error(grpcCode(code), err.Error())
+}
+
+// grpcCodes maps http statuses to the gRPC codes reported for them.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:         codes.InvalidArgument,
+	http.StatusUnauthorized:       codes.Unauthenticated,
+	http.StatusForbidden:          codes.PermissionDenied,
+	http.StatusNotFound:           codes.NotFound,
+	http.StatusConflict:           codes.Aborted,
+	http.StatusPreconditionFailed: codes.FailedPrecondition,
+	http.StatusTooManyRequests:    codes.ResourceExhausted,
+	http.StatusNotImplemented:     codes.Unimplemented,
+	http.StatusBadGateway:         codes.Unavailable,
+	http.StatusServiceUnavailable: codes.Unavailable,
+	http.StatusGatewayTimeout:     codes.DeadlineExceeded,
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	if code, ok := grpcCodes[httpStatus]; ok {
+		return code
+	}
+
+	return codes.Internal
+}
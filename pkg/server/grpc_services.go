@@ -0,0 +1,312 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	bitwardenv1 "github.com/external-secrets/bitwarden-sdk-server/pkg/api/bitwarden/v1"
+)
+
+// grpcSecrets implements the gRPC SecretsService on top of the shared service.
+type grpcSecrets struct {
+	bitwardenv1.UnimplementedSecretsServiceServer
+
+	svc *service
+}
+
+func (g *grpcSecrets) GetSecret(ctx context.Context, req *bitwardenv1.GetSecretRequest) (*bitwardenv1.Secret, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.GetSecret(ctx, c, &sdk.SecretGetRequest{ID: req.GetId()})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSecret(response), nil
+}
+
+func (g *grpcSecrets) GetSecretsByIDs(ctx context.Context, req *bitwardenv1.GetSecretsByIDsRequest) (*bitwardenv1.GetSecretsByIDsResponse, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.GetSecretsByIDs(ctx, c, &sdk.SecretsGetRequest{IDS: req.GetIds()})
+	if err != nil {
+		return nil, err
+	}
+
+	return &bitwardenv1.GetSecretsByIDsResponse{Secrets: toSecrets(response.Data)}, nil
+}
+
+func (g *grpcSecrets) ListSecrets(ctx context.Context, req *bitwardenv1.ListSecretsRequest) (*bitwardenv1.ListSecretsResponse, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.ListSecrets(ctx, c, &ListSecretsRequest{
+		OrganizationID: req.GetOrganizationId(),
+		SecretsFilter: SecretsFilter{
+			KeyPrefix:  req.GetKeyPrefix(),
+			KeyPattern: req.GetKeyPattern(),
+			KeyRegex:   req.GetKeyRegex(),
+			ProjectIDs: req.GetProjectIds(),
+			WithValues: req.GetWithValues(),
+		},
+		Page: Page{Limit: int(req.GetLimit()), ContinuationToken: req.GetContinuationToken(), Sort: req.GetSort()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]*bitwardenv1.SecretIdentifier, 0, len(response.Data))
+	for _, secret := range response.Data {
+		secrets = append(secrets, &bitwardenv1.SecretIdentifier{
+			Id:             secret.ID,
+			OrganizationId: secret.OrganizationID,
+			ProjectIds:     secret.ProjectIDS,
+			Key:            secret.Key,
+		})
+	}
+
+	return &bitwardenv1.ListSecretsResponse{Secrets: secrets, Values: toSecrets(response.Secrets), ContinuationToken: response.ContinuationToken}, nil
+}
+
+func (g *grpcSecrets) SyncSecrets(ctx context.Context, req *bitwardenv1.SyncSecretsRequest) (*bitwardenv1.SyncSecretsResponse, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &sdk.SecretsSyncRequest{OrganizationID: req.GetOrganizationId()}
+	if req.GetLastSyncedDate() != nil {
+		lastSyncedDate := req.GetLastSyncedDate().AsTime()
+		request.LastSyncedDate = &lastSyncedDate
+	}
+
+	response, err := g.svc.SyncSecrets(ctx, c, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bitwardenv1.SyncSecretsResponse{HasChanges: response.HasChanges, Secrets: toSecrets(response.Secrets)}, nil
+}
+
+func (g *grpcSecrets) CreateSecret(ctx context.Context, req *bitwardenv1.CreateSecretRequest) (*bitwardenv1.Secret, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.CreateSecret(ctx, c, &sdk.SecretCreateRequest{
+		OrganizationID: req.GetOrganizationId(),
+		ProjectIDS:     req.GetProjectIds(),
+		Key:            req.GetKey(),
+		Value:          req.GetValue(),
+		Note:           req.GetNote(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSecret(response), nil
+}
+
+func (g *grpcSecrets) UpdateSecret(ctx context.Context, req *bitwardenv1.UpdateSecretRequest) (*bitwardenv1.Secret, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.UpdateSecret(ctx, c, &sdk.SecretPutRequest{
+		ID:             req.GetId(),
+		OrganizationID: req.GetOrganizationId(),
+		ProjectIDS:     req.GetProjectIds(),
+		Key:            req.GetKey(),
+		Value:          req.GetValue(),
+		Note:           req.GetNote(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toSecret(response), nil
+}
+
+func (g *grpcSecrets) DeleteSecrets(ctx context.Context, req *bitwardenv1.DeleteSecretsRequest) (*bitwardenv1.DeleteResponse, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.DeleteSecrets(ctx, c, &sdk.SecretsDeleteRequest{IDS: req.GetIds()})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*bitwardenv1.DeleteResponse_Result, 0, len(response.Data))
+	for _, result := range response.Data {
+		results = append(results, &bitwardenv1.DeleteResponse_Result{Id: result.ID, Error: result.Error})
+	}
+
+	return &bitwardenv1.DeleteResponse{Results: results}, nil
+}
+
+// grpcProjects implements the gRPC ProjectsService on top of the shared service.
+type grpcProjects struct {
+	bitwardenv1.UnimplementedProjectsServiceServer
+
+	svc *service
+}
+
+func (g *grpcProjects) GetProject(ctx context.Context, req *bitwardenv1.GetProjectRequest) (*bitwardenv1.Project, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.GetProject(ctx, c, &sdk.ProjectGetRequest{ID: req.GetId()})
+	if err != nil {
+		return nil, err
+	}
+
+	return toProject(response), nil
+}
+
+func (g *grpcProjects) ListProjects(ctx context.Context, req *bitwardenv1.ListProjectsRequest) (*bitwardenv1.ListProjectsResponse, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.ListProjects(ctx, c, &ListProjectsRequest{
+		OrganizationID: req.GetOrganizationId(),
+		Page:           Page{Limit: int(req.GetLimit()), ContinuationToken: req.GetContinuationToken(), Sort: req.GetSort()},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*bitwardenv1.Project, 0, len(response.Data))
+	for i := range response.Data {
+		projects = append(projects, toProject(&response.Data[i]))
+	}
+
+	return &bitwardenv1.ListProjectsResponse{Projects: projects, ContinuationToken: response.ContinuationToken}, nil
+}
+
+func (g *grpcProjects) CreateProject(ctx context.Context, req *bitwardenv1.CreateProjectRequest) (*bitwardenv1.Project, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.CreateProject(ctx, c, &sdk.ProjectCreateRequest{
+		OrganizationID: req.GetOrganizationId(),
+		Name:           req.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toProject(response), nil
+}
+
+func (g *grpcProjects) UpdateProject(ctx context.Context, req *bitwardenv1.UpdateProjectRequest) (*bitwardenv1.Project, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.UpdateProject(ctx, c, &sdk.ProjectPutRequest{
+		ID:             req.GetId(),
+		OrganizationID: req.GetOrganizationId(),
+		Name:           req.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toProject(response), nil
+}
+
+func (g *grpcProjects) DeleteProjects(ctx context.Context, req *bitwardenv1.DeleteProjectsRequest) (*bitwardenv1.DeleteResponse, error) {
+	c, err := clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := g.svc.DeleteProjects(ctx, c, &sdk.ProjectsDeleteRequest{IDS: req.GetIds()})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*bitwardenv1.DeleteResponse_Result, 0, len(response.Data))
+	for _, result := range response.Data {
+		results = append(results, &bitwardenv1.DeleteResponse_Result{Id: result.ID, Error: result.Error})
+	}
+
+	return &bitwardenv1.DeleteResponse{Results: results}, nil
+}
+
+func toSecret(secret *sdk.SecretResponse) *bitwardenv1.Secret {
+	return &bitwardenv1.Secret{
+		Id:             secret.ID,
+		OrganizationId: secret.OrganizationID,
+		ProjectId:      secret.ProjectID,
+		Key:            secret.Key,
+		Value:          secret.Value,
+		Note:           secret.Note,
+		CreationDate:   toTimestamp(secret.CreationDate),
+		RevisionDate:   toTimestamp(secret.RevisionDate),
+	}
+}
+
+func toSecrets(secrets []sdk.SecretResponse) []*bitwardenv1.Secret {
+	result := make([]*bitwardenv1.Secret, 0, len(secrets))
+	for i := range secrets {
+		result = append(result, toSecret(&secrets[i]))
+	}
+
+	return result
+}
+
+func toProject(project *sdk.ProjectResponse) *bitwardenv1.Project {
+	return &bitwardenv1.Project{
+		Id:             project.ID,
+		OrganizationId: project.OrganizationID,
+		Name:           project.Name,
+		CreationDate:   toTimestamp(project.CreationDate),
+		RevisionDate:   toTimestamp(project.RevisionDate),
+	}
+}
+
+// toTimestamp converts a time to a timestamp, leaving unset times unset.
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,258 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	bitwardenv1 "github.com/external-secrets/bitwarden-sdk-server/pkg/api/bitwarden/v1"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// dialGRPC serves srv on an in-memory listener and returns a connection to it.
+func dialGRPC(t *testing.T, srv *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+func TestGRPCMethods(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(ctx context.Context, conn *grpc.ClientConn) error
+		expectedCall string
+	}{
+		{
+			name: "get secret",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewSecretsServiceClient(conn).GetSecret(ctx, &bitwardenv1.GetSecretRequest{Id: "id-1"})
+				return err
+			},
+			expectedCall: "Get(id-1)",
+		},
+		{
+			name: "get secrets by ids",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewSecretsServiceClient(conn).GetSecretsByIDs(ctx, &bitwardenv1.GetSecretsByIDsRequest{Ids: []string{"id-1", "id-2"}})
+				return err
+			},
+			expectedCall: "GetByIDS([id-1 id-2])",
+		},
+		{
+			name: "list secrets",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewSecretsServiceClient(conn).ListSecrets(ctx, &bitwardenv1.ListSecretsRequest{OrganizationId: "org-1"})
+				return err
+			},
+			expectedCall: "List(org-1)",
+		},
+		{
+			name: "sync secrets since",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewSecretsServiceClient(conn).SyncSecrets(ctx, &bitwardenv1.SyncSecretsRequest{
+					OrganizationId: "org-1",
+					LastSyncedDate: timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+				})
+				return err
+			},
+			expectedCall: "Sync(org-1, 2024-01-02 03:04:05 +0000 UTC)",
+		},
+		{
+			name: "update secret",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewSecretsServiceClient(conn).UpdateSecret(ctx, &bitwardenv1.UpdateSecretRequest{
+					Id: "id-1", Key: "key", Value: "value", Note: "note", OrganizationId: "org-1", ProjectIds: []string{"proj-1"},
+				})
+				return err
+			},
+			expectedCall: "Update(id-1, key, value, note, org-1, [proj-1])",
+		},
+		{
+			name: "delete secrets",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewSecretsServiceClient(conn).DeleteSecrets(ctx, &bitwardenv1.DeleteSecretsRequest{Ids: []string{"id-1"}})
+				return err
+			},
+			expectedCall: "Delete([id-1])",
+		},
+		{
+			name: "get project",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewProjectsServiceClient(conn).GetProject(ctx, &bitwardenv1.GetProjectRequest{Id: "proj-1"})
+				return err
+			},
+			expectedCall: "Get(proj-1)",
+		},
+		{
+			name: "list projects",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewProjectsServiceClient(conn).ListProjects(ctx, &bitwardenv1.ListProjectsRequest{OrganizationId: "org-1"})
+				return err
+			},
+			expectedCall: "List(org-1)",
+		},
+		{
+			name: "update project",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewProjectsServiceClient(conn).UpdateProject(ctx, &bitwardenv1.UpdateProjectRequest{Id: "proj-1", OrganizationId: "org-1", Name: "renamed"})
+				return err
+			},
+			expectedCall: "Update(proj-1, org-1, renamed)",
+		},
+		{
+			name: "delete projects",
+			call: func(ctx context.Context, conn *grpc.ClientConn) error {
+				_, err := bitwardenv1.NewProjectsServiceClient(conn).DeleteProjects(ctx, &bitwardenv1.DeleteProjectsRequest{Ids: []string{"proj-1", "proj-2"}})
+				return err
+			},
+			expectedCall: "Delete([proj-1 proj-2])",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingClient{secrets: &recordingSecrets{}, projects: &recordingProjects{}}
+
+			// Replaces the Warden interceptor, which would log in to Bitwarden.
+			withClient := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				ctx, done := bitwarden.WithClient(ctx, client, "identity", func() {})
+				defer done()
+
+				return handler(ctx, req)
+			}
+			svc := &service{}
+			srv := grpc.NewServer(grpc.ChainUnaryInterceptor(statusInterceptor, withClient))
+			bitwardenv1.RegisterSecretsServiceServer(srv, &grpcSecrets{svc: svc})
+			bitwardenv1.RegisterProjectsServiceServer(srv, &grpcProjects{svc: svc})
+
+			err := tt.call(context.Background(), dialGRPC(t, srv))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCall, client.secrets.call+client.projects.call)
+		})
+	}
+}
+
+func TestGRPCAuthentication(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		metadata     metadata.MD
+		expectedCode codes.Code
+	}{
+		{
+			name:         "missing access token",
+			config:       Config{Insecure: true},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:   "rejected bitwarden url",
+			config: Config{Insecure: true, AllowedHosts: []string{"vault.bitwarden.eu"}},
+			metadata: metadata.Pairs(
+				"warden-access-token", "token",
+				"warden-api-url", "https://attacker.example.com/api",
+			),
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:   "rejected state path",
+			config: Config{Insecure: true, StateDir: "/var/lib/bitwarden"},
+			metadata: metadata.Pairs(
+				"warden-access-token", "token",
+				"warden-state-path", "../../etc/passwd",
+			),
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := NewServer(tt.config).newGRPCServer()
+			require.NoError(t, err)
+
+			ctx := metadata.NewOutgoingContext(context.Background(), tt.metadata)
+			_, err = bitwardenv1.NewSecretsServiceClient(dialGRPC(t, srv)).GetSecret(ctx, &bitwardenv1.GetSecretRequest{Id: "id-1"})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestGRPCReflection(t *testing.T) {
+	srv, err := NewServer(Config{Insecure: true}).newGRPCServer()
+	require.NoError(t, err)
+	assert.NotContains(t, srv.GetServiceInfo(), "grpc.reflection.v1.ServerReflection")
+
+	srv, err = NewServer(Config{Insecure: true, GRPCReflection: true}).newGRPCServer()
+	require.NoError(t, err)
+	assert.Contains(t, srv.GetServiceInfo(), "grpc.reflection.v1.ServerReflection")
+}
+
+func TestGRPCError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+	}{
+		{name: "invalid request", err: apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid"), expectedCode: codes.InvalidArgument},
+		{name: "sdk not found", err: errors.New("failed to get secret: 404 Not Found"), expectedCode: codes.NotFound},
+		{name: "sdk rate limited", err: errors.New("too many requests"), expectedCode: codes.ResourceExhausted},
+		{name: "bitwarden unavailable", err: errors.New("error sending request"), expectedCode: codes.Unavailable},
+		{name: "internal", err: apierror.Errorf(http.StatusInternalServerError, apierror.CodeInternal, "broken"), expectedCode: codes.Internal},
+		{name: "status error", err: status.Error(codes.Canceled, "canceled"), expectedCode: codes.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := grpcError(tt.err)
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Contains(t, tt.err.Error(), status.Convert(err).Message())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+// SecretByKeyRequest selects a secret by its key instead of its ID.
+type SecretByKeyRequest struct {
+	OrganizationID string `json:"organizationId"`
+	Key            string `json:"key"`
+	// ProjectID optionally restricts the lookup to the secrets of a project.
+	ProjectID string `json:"projectId,omitempty"`
+}
+
+func (r *SecretByKeyRequest) validate() error {
+	if r.OrganizationID == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing organizationId")
+	}
+
+	if r.Key == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing key")
+	}
+
+	return nil
+}
+
+// GetSecretByKey returns the secret with the given key. Bitwarden doesn't enforce unique keys, so
+// a key matching no secret or several secrets in the scope is an error.
+func (svc *service) GetSecretByKey(ctx context.Context, c sdk.BitwardenClientInterface, request *SecretByKeyRequest) (*sdk.SecretResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID = "secret.get_by_key", request.OrganizationID
+		if request.ProjectID != "" {
+			e.ProjectIDs = []string{request.ProjectID}
+		}
+	})
+
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := resolveKey(c, request.scope(), request.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.SecretIDs = []string{id}
+	})
+
+	response, err := svc.getSecret(ctx, c, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	return response, nil
+}
+
+// keyScope is the set of secrets a key is looked up in, all secrets of the organization or those
+// in any of the projects.
+type keyScope struct {
+	organizationID string
+	projectIDs     []string
+}
+
+func (s keyScope) String() string {
+	if len(s.projectIDs) > 0 {
+		return fmt.Sprintf("project %s", strings.Join(s.projectIDs, ", "))
+	}
+
+	return fmt.Sprintf("organization %s", s.organizationID)
+}
+
+func (r *SecretByKeyRequest) scope() keyScope {
+	scope := keyScope{organizationID: r.OrganizationID}
+	if r.ProjectID != "" {
+		scope.projectIDs = []string{r.ProjectID}
+	}
+
+	return scope
+}
+
+// findByKey returns the identifiers of the secrets in the scope with the key.
+func findByKey(c sdk.BitwardenClientInterface, scope keyScope, key string) ([]sdk.SecretIdentifierResponse, error) {
+	identifiers, err := c.Secrets().List(scope.organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	var matches []sdk.SecretIdentifierResponse
+	for _, identifier := range identifiers.Data {
+		if identifier.Key != key {
+			continue
+		}
+
+		if len(scope.projectIDs) > 0 && !slices.ContainsFunc(identifier.ProjectIDS, func(id string) bool {
+			return slices.Contains(scope.projectIDs, id)
+		}) {
+			continue
+		}
+
+		matches = append(matches, identifier)
+	}
+
+	return matches, nil
+}
+
+// resolveKey returns the ID of the only secret in the scope with the key.
+func resolveKey(c sdk.BitwardenClientInterface, scope keyScope, key string) (string, error) {
+	matches, err := findByKey(c, scope, key)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", apierror.Errorf(http.StatusNotFound, apierror.CodeNotFound, "no secret with key %q found in %s", key, scope)
+	case 1:
+		return matches[0].ID, nil
+	}
+
+	return "", ambiguousKeyError(scope, key, matches)
+}
+
+// ambiguousKeyError reports that several secrets share the key, listing their IDs so the caller
+// can pick one.
+func ambiguousKeyError(scope keyScope, key string, matches []sdk.SecretIdentifierResponse) error {
+	return apierror.Errorf(http.StatusConflict, apierror.CodeConflict, "%d secrets with key %q found in %s: %s", len(matches), key, scope, strings.Join(secretIDs(matches), ", "))
+}
+
+func (s *Server) getSecretByKeyHandler(w http.ResponseWriter, r *http.Request) {
+	request := &SecretByKeyRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GetSecretByKey(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
+}
+
+func (s *Server) getSecretByKeyV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &SecretByKeyRequest{
+		OrganizationID: chi.URLParam(r, "orgId"),
+		Key:            r.URL.Query().Get("key"),
+		ProjectID:      r.URL.Query().Get("projectId"),
+	}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GetSecretByKey(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
+}
@@ -0,0 +1,129 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/memory"
+)
+
+// newMemoryClient returns a client of an empty in-memory backend.
+func newMemoryClient(t *testing.T) sdk.BitwardenClientInterface {
+	t.Helper()
+
+	client, err := memory.New().Login(context.Background(), &bitwarden.LoginRequest{AccessToken: "token"})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// serveWithClient serves a request with the v1 and v2 routes, authenticated as the client.
+func serveWithClient(s *Server, client sdk.BitwardenClientInterface, method, path, body string) *httptest.ResponseRecorder {
+	return serveRequestWithClient(s, client, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+}
+
+// serveRequestWithClient serves the request like serveWithClient, for requests needing headers.
+func serveRequestWithClient(s *Server, client sdk.BitwardenClientInterface, req *http.Request) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, done := bitwarden.WithClient(r.Context(), client, "identity", func() {})
+			defer done()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Route(api, s.v1Routes)
+	r.Route(apiV2, s.v2Routes)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestGetSecretByKey(t *testing.T) {
+	client := newMemoryClient(t)
+	project, err := client.Projects().Create("org-1", "project")
+	require.NoError(t, err)
+	other, err := client.Projects().Create("org-1", "other")
+	require.NoError(t, err)
+	unique, err := client.Secrets().Create("unique", "unique value", "", "org-1", []string{project.ID})
+	require.NoError(t, err)
+	shared, err := client.Secrets().Create("shared", "shared value", "", "org-1", []string{project.ID})
+	require.NoError(t, err)
+	sharedOther, err := client.Secrets().Create("shared", "other value", "", "org-1", []string{other.ID})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedID     string
+		expectedError  string
+	}{
+		{name: "v1", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "key": "unique"}`, expectedStatus: http.StatusOK, expectedID: unique.ID},
+		{name: "v1 in project", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "key": "shared", "projectId": "` + other.ID + `"}`, expectedStatus: http.StatusOK, expectedID: sharedOther.ID},
+		{name: "v2", path: apiV2 + "/organizations/org-1/secrets/by-key?key=unique", expectedStatus: http.StatusOK, expectedID: unique.ID},
+		{name: "v2 in project", path: apiV2 + "/organizations/org-1/secrets/by-key?key=shared&projectId=" + project.ID, expectedStatus: http.StatusOK, expectedID: shared.ID},
+		{name: "unknown key", path: apiV2 + "/organizations/org-1/secrets/by-key?key=unknown", expectedStatus: http.StatusNotFound, expectedError: `no secret with key "unknown" found in organization org-1`},
+		{name: "key outside of project", path: apiV2 + "/organizations/org-1/secrets/by-key?key=unique&projectId=" + other.ID, expectedStatus: http.StatusNotFound, expectedError: `no secret with key "unique" found in project ` + other.ID},
+		{name: "ambiguous key", path: apiV2 + "/organizations/org-1/secrets/by-key?key=shared", expectedStatus: http.StatusConflict, expectedError: `2 secrets with key "shared" found in organization org-1`},
+		{name: "missing key", path: apiV2 + "/organizations/org-1/secrets/by-key", expectedStatus: http.StatusBadRequest, expectedError: "missing key"},
+		{name: "missing organization", path: api + "/secret-by-key", body: `{"key": "unique"}`, expectedStatus: http.StatusBadRequest, expectedError: "missing organizationId"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClient(NewServer(Config{}), client, http.MethodGet, tt.path, tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedID != "" {
+				var secret sdk.SecretResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &secret))
+				assert.Equal(t, tt.expectedID, secret.ID)
+			} else {
+				var body apierror.Body
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Contains(t, body.Message, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestGetSecretByKeyListError(t *testing.T) {
+	client := &mockClient{secrets: &mockSecrets{listErr: errors.New("API error: [403 Forbidden]")}}
+
+	w := serveWithClient(NewServer(Config{}), client, http.MethodGet, apiV2+"/organizations/org-1/secrets/by-key?key=key", "")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "failed to list secrets")
+}
@@ -0,0 +1,388 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// The OpenAPI document is generated from the operations below and the Go types of their request
+// and response bodies. Every route registered in routes has to be listed here, which is
+// enforced by TestOpenAPICoversAllRoutes.
+
+const (
+	openAPIVersion = "3.1.0"
+	openAPIPath    = "/openapi.json"
+
+	contentTypeJSON = "application/json"
+	contentTypeText = "text/plain"
+)
+
+// apiOperation describes a single route of the API.
+type apiOperation struct {
+	method  string
+	path    string
+	id      string
+	summary string
+	params  []openAPIParameter
+	// request is the type of the JSON body, nil if the operation has none.
+	request any
+	// response is the type of the JSON response. A string means a plain text response.
+	response any
+	// alternatives are further media types of the response, described as strings.
+	alternatives []string
+	// warden marks operations authenticated by the Warden headers.
+	warden bool
+	// dryRun marks operations supporting dry runs, which respond with a DryRunResponse.
+	dryRun bool
+}
+
+var (
+	idParam      = openAPIParameter{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "string"}}
+	orgIDParam   = openAPIParameter{Name: "orgId", In: "path", Required: true, Schema: &openAPISchema{Type: "string"}}
+	idsParam     = openAPIParameter{Name: "ids", In: "query", Required: true, Description: "Comma separated or repeated IDs.", Schema: &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string"}}, Explode: true}
+	keyParam     = openAPIParameter{Name: "key", In: "query", Required: true, Description: "Key of the secret.", Schema: &openAPISchema{Type: "string"}}
+	projectParam = openAPIParameter{Name: "projectId", In: "query", Description: "Only consider the secrets of this project.", Schema: &openAPISchema{Type: "string"}}
+	lastSyncDate = openAPIParameter{Name: "lastSyncedDate", In: "query", Description: "Only return secrets changed after this date.", Schema: &openAPISchema{Type: "string", Format: "date-time"}}
+
+	keyPrefixParam  = openAPIParameter{Name: "keyPrefix", In: "query", Description: "Only return secrets with keys starting with the prefix.", Schema: &openAPISchema{Type: "string"}}
+	keyPatternParam = openAPIParameter{Name: "keyPattern", In: "query", Description: "Only return secrets with keys matching the glob pattern.", Schema: &openAPISchema{Type: "string"}}
+	keyRegexParam   = openAPIParameter{Name: "keyRegex", In: "query", Description: "Only return secrets with keys matching the regular expression.", Schema: &openAPISchema{Type: "string"}}
+	projectIDsParam = openAPIParameter{Name: "projectId", In: "query", Description: "Only return secrets in any of the comma separated or repeated projects.", Schema: &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string"}}, Explode: true}
+	withValuesParam = openAPIParameter{Name: "withValues", In: "query", Description: "Also return the matching secrets including their values.", Schema: &openAPISchema{Type: "boolean"}}
+
+	limitParam             = openAPIParameter{Name: "limit", In: "query", Description: "Maximum number of items returned, all items are returned if unset.", Schema: &openAPISchema{Type: "integer"}}
+	continuationTokenParam = openAPIParameter{Name: "continuationToken", In: "query", Description: "Token returned with the previous page, to get the next one.", Schema: &openAPISchema{Type: "string"}}
+	secretSortParam        = openAPIParameter{Name: "sort", In: "query", Description: "Sort by key (the default) or revisionDate, limited to 1000 matching secrets, prefix with - to sort descending.", Schema: &openAPISchema{Type: "string"}}
+	projectSortParam       = openAPIParameter{Name: "sort", In: "query", Description: "Sort by name (the default) or revisionDate, prefix with - to sort descending.", Schema: &openAPISchema{Type: "string"}}
+
+	ifMatchParam     = openAPIParameter{Name: "If-Match", In: "header", Description: "Only change the secret if its current ETag matches, fails with 412 otherwise.", Schema: &openAPISchema{Type: "string"}}
+	ifNoneMatchParam = openAPIParameter{Name: "If-None-Match", In: "header", Description: "Return 304 Not Modified if the ETag of the secret matches.", Schema: &openAPISchema{Type: "string"}}
+
+	exportOrgIDParam     = openAPIParameter{Name: "organizationId", In: "query", Description: "Export the secrets of the organization.", Schema: &openAPISchema{Type: "string"}}
+	exportIDsParam       = openAPIParameter{Name: "ids", In: "query", Description: "Export the secrets with the comma separated or repeated IDs instead.", Schema: &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string"}}, Explode: true}
+	exportFormatParam    = openAPIParameter{Name: "format", In: "query", Description: "dotenv, yaml, json or kubernetes, taken from the Accept header if unset.", Schema: &openAPISchema{Type: "string"}}
+	exportNameParam      = openAPIParameter{Name: "name", In: "query", Description: "Name of the Kubernetes Secret, required for the kubernetes format.", Schema: &openAPISchema{Type: "string"}}
+	exportNamespaceParam = openAPIParameter{Name: "namespace", In: "query", Description: "Namespace of the Kubernetes Secret.", Schema: &openAPISchema{Type: "string"}}
+
+	dryRunParam           = openAPIParameter{Name: "dryRun", In: "query", Description: "Only return the changes the request would make, without making them.", Schema: &openAPISchema{Type: "boolean"}}
+	dryRunWithValuesParam = openAPIParameter{Name: "withValues", In: "query", Description: "Include secret values in the changes of a dry run.", Schema: &openAPISchema{Type: "boolean"}}
+)
+
+func apiOperations() []apiOperation {
+	return []apiOperation{
+		{method: http.MethodGet, path: "/ready", id: "ready", summary: "Readiness probe.", response: ""},
+		{method: http.MethodGet, path: "/live", id: "live", summary: "Liveness probe.", response: ""},
+		{method: http.MethodGet, path: "/metrics", id: "metrics", summary: "Prometheus metrics, unless served on a separate listener.", response: ""},
+		{method: http.MethodGet, path: openAPIPath, id: "openapi", summary: "This OpenAPI document.", response: map[string]any{}},
+
+		{method: http.MethodGet, path: api + "/secret", id: "getSecret", summary: "Get a secret.", params: []openAPIParameter{ifNoneMatchParam}, request: sdk.SecretGetRequest{}, response: sdk.SecretResponse{}, warden: true},
+		{method: http.MethodGet, path: api + "/secret-by-key", id: "getSecretByKey", summary: "Get a secret by its key.", params: []openAPIParameter{ifNoneMatchParam}, request: SecretByKeyRequest{}, response: sdk.SecretResponse{}, warden: true},
+		{method: http.MethodGet, path: api + "/secrets", id: "listSecrets", summary: "List the secrets of an organization.", request: ListSecretsRequest{}, response: SecretsListResponse{}, warden: true},
+		{method: http.MethodGet, path: api + "/secrets-by-ids", id: "getSecretsByIDs", summary: "Get secrets by their IDs.", request: sdk.SecretsGetRequest{}, response: sdk.SecretsResponse{}, warden: true},
+		{method: http.MethodGet, path: api + "/secrets/sync", id: "syncSecrets", summary: "Get the secrets of an organization changed since the last sync.", request: sdk.SecretsSyncRequest{}, response: sdk.SecretsSyncResponse{}, warden: true},
+		{method: http.MethodDelete, path: api + "/secret", id: "deleteSecrets", summary: "Delete secrets.", params: []openAPIParameter{ifMatchParam}, request: sdk.SecretsDeleteRequest{}, response: sdk.SecretsDeleteResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: api + "/secret", id: "createSecret", summary: "Create a secret.", request: sdk.SecretCreateRequest{}, response: sdk.SecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: api + "/secret", id: "updateSecret", summary: "Update a secret.", params: []openAPIParameter{ifMatchParam}, request: sdk.SecretPutRequest{}, response: sdk.SecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPatch, path: api + "/secret", id: "patchSecret", summary: "Change some fields of a secret.", params: []openAPIParameter{ifMatchParam}, request: PatchSecretRequest{}, response: sdk.SecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: api + "/secret-by-key", id: "upsertSecret", summary: "Create or update a secret by its key.", request: UpsertSecretRequest{}, response: UpsertSecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: api + "/secrets/bulk", id: "createSecrets", summary: "Create several secrets.", request: BulkCreateSecretsRequest{}, response: BulkSecretsResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: api + "/secrets/bulk", id: "updateSecrets", summary: "Update several secrets.", request: BulkUpdateSecretsRequest{}, response: BulkSecretsResponse{}, warden: true, dryRun: true},
+		{method: http.MethodGet, path: api + "/export", id: "exportSecrets", summary: "Export the values of secrets by their key.", params: []openAPIParameter{exportFormatParam}, request: ExportSecretsRequest{}, response: map[string]string{}, alternatives: []string{contentTypeText, contentTypeYAML}, warden: true},
+		{method: http.MethodGet, path: api + "/project", id: "getProject", summary: "Get a project.", request: sdk.ProjectGetRequest{}, response: sdk.ProjectResponse{}, warden: true},
+		{method: http.MethodGet, path: api + "/projects", id: "listProjects", summary: "List the projects of an organization.", request: ListProjectsRequest{}, response: ProjectsListResponse{}, warden: true},
+		{method: http.MethodDelete, path: api + "/project", id: "deleteProjects", summary: "Delete projects.", request: sdk.ProjectsDeleteRequest{}, response: sdk.ProjectsDeleteResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: api + "/project", id: "createProject", summary: "Create a project.", request: sdk.ProjectCreateRequest{}, response: sdk.ProjectResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: api + "/project", id: "updateProject", summary: "Update a project.", request: sdk.ProjectPutRequest{}, response: sdk.ProjectResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: api + "/generators/password", id: "generatePassword", summary: "Generate a password.", request: sdk.PasswordGeneratorRequest{}, response: PasswordResponse{}, warden: true},
+
+		{method: http.MethodGet, path: apiV2 + "/secrets", id: "getSecretsByIDsV2", summary: "Get secrets by their IDs.", params: []openAPIParameter{idsParam}, response: sdk.SecretsResponse{}, warden: true},
+		{method: http.MethodGet, path: apiV2 + "/secrets/{id}", id: "getSecretV2", summary: "Get a secret.", params: []openAPIParameter{idParam, ifNoneMatchParam}, response: sdk.SecretResponse{}, warden: true},
+		{method: http.MethodDelete, path: apiV2 + "/secrets", id: "deleteSecretsV2", summary: "Delete secrets.", params: []openAPIParameter{idsParam, ifMatchParam}, response: sdk.SecretsDeleteResponse{}, warden: true, dryRun: true},
+		{method: http.MethodDelete, path: apiV2 + "/secrets/{id}", id: "deleteSecretV2", summary: "Delete a secret.", params: []openAPIParameter{idParam, ifMatchParam}, response: sdk.SecretsDeleteResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: apiV2 + "/secrets", id: "createSecretV2", summary: "Create a secret.", request: sdk.SecretCreateRequest{}, response: sdk.SecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: apiV2 + "/secrets/{id}", id: "updateSecretV2", summary: "Update a secret.", params: []openAPIParameter{idParam, ifMatchParam}, request: sdk.SecretPutRequest{}, response: sdk.SecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPatch, path: apiV2 + "/secrets/{id}", id: "patchSecretV2", summary: "Change some fields of a secret.", params: []openAPIParameter{idParam, ifMatchParam}, request: PatchSecretRequest{}, response: sdk.SecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodGet, path: apiV2 + "/organizations/{orgId}/secrets", id: "listSecretsV2", summary: "List the secrets of an organization.", params: []openAPIParameter{orgIDParam, keyPrefixParam, keyPatternParam, keyRegexParam, projectIDsParam, withValuesParam, limitParam, continuationTokenParam, secretSortParam}, response: SecretsListResponse{}, warden: true},
+		{method: http.MethodGet, path: apiV2 + "/organizations/{orgId}/secrets/sync", id: "syncSecretsV2", summary: "Get the secrets of an organization changed since the last sync.", params: []openAPIParameter{orgIDParam, lastSyncDate}, response: sdk.SecretsSyncResponse{}, warden: true},
+		{method: http.MethodGet, path: apiV2 + "/organizations/{orgId}/secrets/by-key", id: "getSecretByKeyV2", summary: "Get a secret by its key.", params: []openAPIParameter{orgIDParam, keyParam, projectParam, ifNoneMatchParam}, response: sdk.SecretResponse{}, warden: true},
+		{method: http.MethodPut, path: apiV2 + "/organizations/{orgId}/secrets/by-key", id: "upsertSecretV2", summary: "Create or update a secret by its key.", params: []openAPIParameter{orgIDParam}, request: UpsertSecretRequest{}, response: UpsertSecretResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: apiV2 + "/secrets/bulk", id: "createSecretsV2", summary: "Create several secrets.", request: BulkCreateSecretsRequest{}, response: BulkSecretsResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: apiV2 + "/secrets/bulk", id: "updateSecretsV2", summary: "Update several secrets.", request: BulkUpdateSecretsRequest{}, response: BulkSecretsResponse{}, warden: true, dryRun: true},
+		{method: http.MethodGet, path: apiV2 + "/export", id: "exportSecretsV2", summary: "Export the values of secrets by their key.", params: []openAPIParameter{exportOrgIDParam, projectIDsParam, exportIDsParam, exportFormatParam, exportNameParam, exportNamespaceParam}, response: map[string]string{}, alternatives: []string{contentTypeText, contentTypeYAML}, warden: true},
+		{method: http.MethodGet, path: apiV2 + "/projects/{id}", id: "getProjectV2", summary: "Get a project.", params: []openAPIParameter{idParam}, response: sdk.ProjectResponse{}, warden: true},
+		{method: http.MethodDelete, path: apiV2 + "/projects", id: "deleteProjectsV2", summary: "Delete projects.", params: []openAPIParameter{idsParam}, response: sdk.ProjectsDeleteResponse{}, warden: true, dryRun: true},
+		{method: http.MethodDelete, path: apiV2 + "/projects/{id}", id: "deleteProjectV2", summary: "Delete a project.", params: []openAPIParameter{idParam}, response: sdk.ProjectsDeleteResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPost, path: apiV2 + "/projects", id: "createProjectV2", summary: "Create a project.", request: sdk.ProjectCreateRequest{}, response: sdk.ProjectResponse{}, warden: true, dryRun: true},
+		{method: http.MethodPut, path: apiV2 + "/projects/{id}", id: "updateProjectV2", summary: "Update a project.", params: []openAPIParameter{idParam}, request: sdk.ProjectPutRequest{}, response: sdk.ProjectResponse{}, warden: true, dryRun: true},
+		{method: http.MethodGet, path: apiV2 + "/organizations/{orgId}/projects", id: "listProjectsV2", summary: "List the projects of an organization.", params: []openAPIParameter{orgIDParam, limitParam, continuationTokenParam, projectSortParam}, response: ProjectsListResponse{}, warden: true},
+		{method: http.MethodPost, path: apiV2 + "/generators/password", id: "generatePasswordV2", summary: "Generate a password.", request: sdk.PasswordGeneratorRequest{}, response: PasswordResponse{}, warden: true},
+	}
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	Parameters      map[string]*openAPIParameter      `json:"parameters"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `json:"$ref,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	In          string         `json:"in,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Explode     bool           `json:"explode,omitempty"`
+	Schema      *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+}
+
+const wardenSecurityScheme = "WardenAccessToken"
+
+// wardenParameters are the optional Warden headers next to the access token.
+var wardenParameters = map[string]*openAPIParameter{
+	"WardenStatePath":   {Name: bitwarden.WardenHeaderStatePath, In: "header", Description: "Path of the state file of the Bitwarden client.", Schema: &openAPISchema{Type: "string"}},
+	"WardenApiUrl":      {Name: bitwarden.WardenHeaderAPIURL, In: "header", Description: "Bitwarden API URL, defaults to the Bitwarden cloud.", Schema: &openAPISchema{Type: "string", Format: "uri"}},
+	"WardenIdentityUrl": {Name: bitwarden.WardenHeaderIdentityURL, In: "header", Description: "Bitwarden identity URL, defaults to the Bitwarden cloud.", Schema: &openAPISchema{Type: "string", Format: "uri"}},
+}
+
+// openAPISpec returns the OpenAPI document encoded as JSON. It only depends on code, so it's
+// generated once.
+var openAPISpec = sync.OnceValues(func() ([]byte, error) {
+	return json.Marshal(newOpenAPIDocument(apiOperations()))
+})
+
+func newOpenAPIDocument(operations []apiOperation) *openAPIDocument {
+	g := &schemaGenerator{schemas: map[string]*openAPISchema{}}
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:       "bitwarden-sdk-server",
+			Description: "REST wrapper for the Bitwarden Secrets Manager SDK.",
+			Version:     "1.0.0",
+		},
+		Paths: map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas:    g.schemas,
+			Parameters: wardenParameters,
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				wardenSecurityScheme: {Type: "apiKey", In: "header", Name: bitwarden.WardenHeaderAccessToken, Description: "Bitwarden machine account access token."},
+			},
+		},
+	}
+	errorSchema := g.schemaFor(reflect.TypeFor[apierror.Body]())
+
+	for _, op := range operations {
+		o := &openAPIOperation{
+			OperationID: op.id,
+			Summary:     op.summary,
+			Parameters:  op.params,
+			Responses:   map[string]*openAPIResponse{},
+		}
+
+		if op.request != nil {
+			o.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]*openAPIMediaType{contentTypeJSON: {Schema: g.schemaFor(reflect.TypeOf(op.request))}},
+			}
+		}
+
+		if _, ok := op.response.(string); ok {
+			o.Responses["200"] = &openAPIResponse{Description: "OK", Content: map[string]*openAPIMediaType{contentTypeText: {Schema: &openAPISchema{Type: "string"}}}}
+		} else {
+			schema := g.schemaFor(reflect.TypeOf(op.response))
+			if op.dryRun {
+				schema = &openAPISchema{OneOf: []*openAPISchema{schema, g.schemaFor(reflect.TypeFor[DryRunResponse]())}}
+				o.Parameters = append(o.Parameters, dryRunParam, dryRunWithValuesParam)
+			}
+			o.Responses["200"] = &openAPIResponse{Description: "OK", Content: map[string]*openAPIMediaType{contentTypeJSON: {Schema: schema}}}
+		}
+
+		for _, contentType := range op.alternatives {
+			o.Responses["200"].Content[contentType] = &openAPIMediaType{Schema: &openAPISchema{Type: "string"}}
+		}
+
+		if op.warden {
+			o.Security = []map[string][]string{{wardenSecurityScheme: {}}}
+			for _, name := range []string{"WardenStatePath", "WardenApiUrl", "WardenIdentityUrl"} {
+				o.Parameters = append(o.Parameters, openAPIParameter{Ref: "#/components/parameters/" + name})
+			}
+			o.Responses["default"] = &openAPIResponse{Description: "Error", Content: map[string]*openAPIMediaType{contentTypeJSON: {Schema: errorSchema}}}
+		}
+
+		if doc.Paths[op.path] == nil {
+			doc.Paths[op.path] = map[string]*openAPIOperation{}
+		}
+		doc.Paths[op.path][strings.ToLower(op.method)] = o
+	}
+
+	return doc
+}
+
+// schemaGenerator derives JSON schemas from Go types the way encoding/json encodes them. Named
+// structs are added to schemas and referenced.
+type schemaGenerator struct {
+	schemas map[string]*openAPISchema
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeFor[time.Time]() {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+
+		return &openAPISchema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+
+	return &openAPISchema{}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *openAPISchema {
+	name := t.Name()
+	ref := &openAPISchema{Ref: "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok && name != "" {
+		return ref
+	}
+
+	schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	if name != "" {
+		// Register the schema before generating its fields, so recursive types terminate.
+		g.schemas[name] = schema
+	}
+
+	for field := range t.Fields() {
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		fieldName, opts, _ := strings.Cut(tag, ",")
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+
+		schema.Properties[fieldName] = g.schemaFor(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, fieldName)
+		}
+	}
+
+	if name == "" {
+		return schema
+	}
+
+	return ref
+}
+
+func (s *Server) openAPIHandler(w http.ResponseWriter, _ *http.Request) {
+	spec, err := openAPISpec()
+	if err != nil {
+		apierror.WriteStatus(w, nil, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_, _ = w.Write(spec)
+}
@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	doc := newOpenAPIDocument(apiOperations())
+
+	var routes []string
+	err := chi.Walk(NewServer(Config{}).routes(), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+strings.TrimSuffix(route, "/"))
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, routes)
+
+	var documented []string
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	assert.ElementsMatch(t, routes, documented, "every route has to be described in apiOperations")
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	doc := newOpenAPIDocument(apiOperations())
+	content, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	// Every reference has to point to a component.
+	for _, m := range regexp.MustCompile(`"\$ref":"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(content), -1) {
+		switch m[1] {
+		case "schemas":
+			assert.Contains(t, doc.Components.Schemas, m[2])
+		case "parameters":
+			assert.Contains(t, doc.Components.Parameters, m[2])
+		default:
+			t.Errorf("unexpected reference %s", m[0])
+		}
+	}
+
+	ids := map[string]bool{}
+	for path, operations := range doc.Paths {
+		for _, name := range regexp.MustCompile(`\{(\w+)\}`).FindAllStringSubmatch(path, -1) {
+			for method, op := range operations {
+				assert.True(t, hasPathParameter(op, name[1]), "%s %s misses path parameter %s", method, path, name[1])
+			}
+		}
+
+		for _, op := range operations {
+			assert.False(t, ids[op.OperationID], "duplicate operation id %s", op.OperationID)
+			ids[op.OperationID] = true
+		}
+	}
+
+	secret := doc.Components.Schemas["SecretResponse"]
+	require.NotNil(t, secret)
+	assert.Equal(t, "date-time", secret.Properties["creationDate"].Format)
+	assert.Contains(t, secret.Required, "value")
+	assert.NotContains(t, secret.Required, "projectId")
+}
+
+func hasPathParameter(op *openAPIOperation, name string) bool {
+	for _, p := range op.Parameters {
+		if p.In == "path" && p.Name == name && p.Required {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewServer(Config{}).routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, openAPIPath, http.NoBody))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, contentTypeJSON, w.Header().Get("Content-Type"))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, openAPIVersion, doc["openapi"])
+}
@@ -0,0 +1,206 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+const (
+	// maxPageLimit is the largest number of items a page can be requested with.
+	maxPageLimit = 1000
+	// maxRevisionDateSortSecrets is the largest number of matching secrets that can be sorted by
+	// revision date. Every one of them is fetched, with its value, for every page.
+	maxRevisionDateSortSecrets = 1000
+)
+
+// Fields lists can be sorted by. Prefixing a field with `-` sorts in descending order.
+const (
+	sortByKey          = "key"
+	sortByName         = "name"
+	sortByRevisionDate = "revisionDate"
+)
+
+// Page selects a page of a list. Lists are always sorted, by default by key or name, so pages are
+// stable. Without a limit the whole list is returned.
+type Page struct {
+	// Limit is the maximum number of items returned.
+	Limit int `json:"limit,omitempty"`
+	// ContinuationToken is the token returned with the previous page, to get the next one.
+	ContinuationToken string `json:"continuationToken,omitempty"`
+	// Sort is the field the list is sorted by, optionally prefixed with `-` to sort in
+	// descending order.
+	Sort string `json:"sort,omitempty"`
+}
+
+// ListProjectsRequest lists the projects of an organization, optionally paginated.
+type ListProjectsRequest struct {
+	OrganizationID string `json:"organizationId"`
+
+	Page
+}
+
+// ProjectsListResponse contains the listed projects.
+type ProjectsListResponse struct {
+	Data []sdk.ProjectResponse `json:"data"`
+	// ContinuationToken is set if there are more projects, pass it to get the next page.
+	ContinuationToken string `json:"continuationToken,omitempty"`
+}
+
+// cursor is the position of the last item of a page, encoded as the continuation token. Pages
+// continue after the position, so items created or deleted in the meantime don't shift them.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"i"`
+}
+
+// sortKey is the position of an item in a sorted list. The ID breaks ties.
+type sortKey struct {
+	value string
+	id    string
+}
+
+func compareSortKeys(a, b sortKey) int {
+	return cmp.Or(strings.Compare(a.value, b.value), strings.Compare(a.id, b.id))
+}
+
+// sortableTime formats a time so that times sort in the same order as their formatted values.
+func sortableTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// sortField returns the field of the sort order and whether it's descending. An empty sort
+// selects the default field, the first of the fields.
+func (p *Page) sortField(fields ...string) (string, bool, error) {
+	if p.Sort == "" {
+		return fields[0], false, nil
+	}
+
+	field, desc := strings.CutPrefix(p.Sort, "-")
+	if !slices.Contains(fields, field) {
+		return "", false, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid sort %q, must be one of %s, optionally prefixed with -", p.Sort, strings.Join(fields, ", "))
+	}
+
+	return field, desc, nil
+}
+
+// paginate sorts the items by the keys returned by key for the field and returns the requested
+// page of them, and the continuation token of the next page. The token is empty for the last page.
+func paginate[T any](items []T, page *Page, field string, desc bool, key func(T) sortKey) ([]T, string, error) {
+	if page.Limit < 0 || page.Limit > maxPageLimit {
+		return nil, "", apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid limit %d, must be between 0 and %d", page.Limit, maxPageLimit)
+	}
+	sort := sortName(field, desc)
+
+	compare := func(a, b sortKey) int {
+		if desc {
+			return compareSortKeys(b, a)
+		}
+
+		return compareSortKeys(a, b)
+	}
+	slices.SortFunc(items, func(a, b T) int {
+		return compare(key(a), key(b))
+	})
+
+	if page.ContinuationToken != "" {
+		after, err := decodeCursor(page.ContinuationToken, sort)
+		if err != nil {
+			return nil, "", err
+		}
+
+		start, found := slices.BinarySearchFunc(items, after, func(item T, after sortKey) int {
+			return compare(key(item), after)
+		})
+		if found {
+			start++
+		}
+		items = items[start:]
+	}
+
+	if page.Limit == 0 || len(items) <= page.Limit {
+		return items, "", nil
+	}
+
+	items = items[:page.Limit]
+	last := key(items[len(items)-1])
+
+	return items, encodeCursor(cursor{Sort: sort, Value: last.value, ID: last.id}), nil
+}
+
+func encodeCursor(c cursor) string {
+	content, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+// decodeCursor decodes a continuation token. Tokens are only valid for the sort order they were
+// created with.
+func decodeCursor(token, sort string) (sortKey, error) {
+	var c cursor
+	content, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(content, &c)
+	}
+	if err != nil {
+		return sortKey{}, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid continuationToken")
+	}
+
+	if c.Sort != sort {
+		return sortKey{}, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "continuationToken was created for sort %q, not %q", c.Sort, sort)
+	}
+
+	return sortKey{value: c.Value, id: c.ID}, nil
+}
+
+// queryPage returns the page selected by the query parameters of the request.
+func queryPage(query url.Values) (Page, error) {
+	page := Page{
+		ContinuationToken: query.Get("continuationToken"),
+		Sort:              query.Get("sort"),
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return Page{}, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid limit: %w", err)
+		}
+		page.Limit = limit
+	}
+
+	return page, nil
+}
+
+// sortName returns the name of the sort order, as it's passed in requests.
+func sortName(field string, desc bool) string {
+	if desc {
+		return "-" + field
+	}
+
+	return field
+}
@@ -0,0 +1,192 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listSecretKeys lists the secrets of org-1 page by page and returns their keys in order.
+func listSecretKeys(t *testing.T, s *Server, client sdk.BitwardenClientInterface, query url.Values) []string {
+	t.Helper()
+
+	var keys []string
+	for range 10 {
+		w := serveWithClient(s, client, http.MethodGet, apiV2+"/organizations/org-1/secrets?"+query.Encode(), "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response SecretsListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		for _, secret := range response.Data {
+			keys = append(keys, secret.Key)
+		}
+
+		if response.ContinuationToken == "" {
+			return keys
+		}
+		query.Set("continuationToken", response.ContinuationToken)
+	}
+
+	t.Fatal("list didn't end")
+
+	return nil
+}
+
+func TestListSecretsPagination(t *testing.T) {
+	client := newMemoryClient(t)
+	ids := map[string]string{}
+	for _, key := range []string{"c", "a", "e", "b", "d"} {
+		secret, err := client.Secrets().Create(key, "value", "", "org-1", nil)
+		require.NoError(t, err)
+		ids[key] = secret.ID
+	}
+	// Moves c to the end of the revision dates.
+	_, err := client.Secrets().Update(ids["c"], "c", "changed", "", "org-1", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		query        url.Values
+		expectedKeys []string
+	}{
+		{name: "all", query: url.Values{}, expectedKeys: []string{"a", "b", "c", "d", "e"}},
+		{name: "pages by key", query: url.Values{"limit": {"2"}}, expectedKeys: []string{"a", "b", "c", "d", "e"}},
+		{name: "pages by key descending", query: url.Values{"limit": {"2"}, "sort": {"-key"}}, expectedKeys: []string{"e", "d", "c", "b", "a"}},
+		{name: "pages by revision date", query: url.Values{"limit": {"3"}, "sort": {"revisionDate"}}, expectedKeys: []string{"a", "e", "b", "d", "c"}},
+		{name: "pages by revision date descending", query: url.Values{"limit": {"1"}, "sort": {"-revisionDate"}}, expectedKeys: []string{"c", "d", "b", "e", "a"}},
+		{name: "filtered pages", query: url.Values{"limit": {"1"}, "keyRegex": {"[a-c]"}}, expectedKeys: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedKeys, listSecretKeys(t, NewServer(Config{}), client, tt.query))
+		})
+	}
+}
+
+func TestListSecretsPaginationIsStable(t *testing.T) {
+	client := newMemoryClient(t)
+	ids := map[string]string{}
+	for _, key := range []string{"b", "d", "f"} {
+		secret, err := client.Secrets().Create(key, "value", "", "org-1", nil)
+		require.NoError(t, err)
+		ids[key] = secret.ID
+	}
+	s := NewServer(Config{})
+
+	w := serveWithClient(s, client, http.MethodGet, apiV2+"/organizations/org-1/secrets?limit=2&withValues=true", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var first SecretsListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	require.Len(t, first.Data, 2)
+	require.Len(t, first.Secrets, 2)
+	assert.Equal(t, first.Data[1].ID, first.Secrets[1].ID)
+
+	// Neither deleting a listed secret nor creating one before the position shifts the next page.
+	_, err := client.Secrets().Delete([]string{ids["b"]})
+	require.NoError(t, err)
+	_, err = client.Secrets().Create("a", "value", "", "org-1", nil)
+	require.NoError(t, err)
+	_, err = client.Secrets().Create("e", "value", "", "org-1", nil)
+	require.NoError(t, err)
+
+	keys := listSecretKeys(t, s, client, url.Values{"limit": {"2"}, "continuationToken": {first.ContinuationToken}})
+	assert.Equal(t, []string{"e", "f"}, keys)
+}
+
+func TestListProjectsPagination(t *testing.T) {
+	client := newMemoryClient(t)
+	for _, name := range []string{"b", "c", "a"} {
+		_, err := client.Projects().Create("org-1", name)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		body          string
+		expectedNames []string
+		expectedToken bool
+	}{
+		{name: "v1 all", path: api + "/projects", body: `{"organizationId": "org-1"}`, expectedNames: []string{"a", "b", "c"}},
+		{name: "v1 page", path: api + "/projects", body: `{"organizationId": "org-1", "limit": 2, "sort": "-name"}`, expectedNames: []string{"c", "b"}, expectedToken: true},
+		{name: "v2 page", path: apiV2 + "/organizations/org-1/projects?limit=2", expectedNames: []string{"a", "b"}, expectedToken: true},
+		{name: "v2 last page", path: apiV2 + "/organizations/org-1/projects?limit=3&sort=revisionDate", expectedNames: []string{"b", "c", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClient(NewServer(Config{}), client, http.MethodGet, tt.path, tt.body)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var response ProjectsListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			var names []string
+			for _, project := range response.Data {
+				names = append(names, project.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			assert.Equal(t, tt.expectedToken, response.ContinuationToken != "")
+		})
+	}
+}
+
+func TestPaginationErrors(t *testing.T) {
+	client := newMemoryClient(t)
+	for _, key := range []string{"a", "b"} {
+		_, err := client.Secrets().Create(key, "value", "", "org-1", nil)
+		require.NoError(t, err)
+	}
+	for range maxRevisionDateSortSecrets + 1 {
+		_, err := client.Secrets().Create("key", "value", "", "org-2", nil)
+		require.NoError(t, err)
+	}
+
+	w := serveWithClient(NewServer(Config{}), client, http.MethodGet, apiV2+"/organizations/org-1/secrets?limit=1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response SecretsListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.ContinuationToken)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "invalid limit", path: apiV2 + "/organizations/org-1/secrets?limit=ten"},
+		{name: "negative limit", path: apiV2 + "/organizations/org-1/secrets?limit=-1"},
+		{name: "limit too large", path: apiV2 + "/organizations/org-1/secrets?limit=1001"},
+		{name: "unknown sort", path: apiV2 + "/organizations/org-1/secrets?sort=value"},
+		{name: "project sort for secrets", path: apiV2 + "/organizations/org-1/secrets?sort=name"},
+		{name: "secret sort for projects", path: apiV2 + "/organizations/org-1/projects?sort=key"},
+		{name: "too many secrets to sort by revision date", path: apiV2 + "/organizations/org-2/secrets?sort=revisionDate&limit=10"},
+		{name: "invalid token", path: apiV2 + "/organizations/org-1/secrets?continuationToken=garbage"},
+		{name: "token of another sort", path: apiV2 + "/organizations/org-1/secrets?sort=-key&continuationToken=" + response.ContinuationToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClient(NewServer(Config{}), client, http.MethodGet, tt.path, "")
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+// PatchSecretRequest changes some fields of a secret, following JSON Merge Patch (RFC 7396).
+// Fields that are missing are kept, fields set to null are cleared. Key and organizationId
+// can't be cleared.
+type PatchSecretRequest struct {
+	ID             string    `json:"id"`
+	Key            *string   `json:"key,omitempty"`
+	Value          *string   `json:"value,omitempty"`
+	Note           *string   `json:"note,omitempty"`
+	OrganizationID *string   `json:"organizationId,omitempty"`
+	ProjectIDs     *[]string `json:"projectIds,omitempty"`
+
+	// IfMatch is the If-Match header of the request, compared against the current secret.
+	IfMatch string `json:"-"`
+}
+
+// UnmarshalJSON decodes a merge patch. Unknown fields and fields of the wrong type are rejected,
+// so a typo doesn't silently leave the secret unchanged.
+func (p *PatchSecretRequest) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	for name, raw := range fields {
+		null := string(raw) == "null"
+
+		var err error
+		switch name {
+		case "id":
+			err = json.Unmarshal(raw, &p.ID)
+		case "key":
+			p.Key, err = decodePatchString(raw, null, false)
+		case "organizationId":
+			p.OrganizationID, err = decodePatchString(raw, null, false)
+		case "value":
+			p.Value, err = decodePatchString(raw, null, true)
+		case "note":
+			p.Note, err = decodePatchString(raw, null, true)
+		case "projectIds":
+			projectIDs := []string{}
+			if !null {
+				err = json.Unmarshal(raw, &projectIDs)
+			}
+			p.ProjectIDs = &projectIDs
+		default:
+			err = fmt.Errorf("unknown field")
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// decodePatchString decodes a string field of a patch. Null clears the field if it's nullable.
+func decodePatchString(raw json.RawMessage, null, nullable bool) (*string, error) {
+	var value string
+	if null {
+		if !nullable {
+			return nil, fmt.Errorf("can't be null")
+		}
+
+		return &value, nil
+	}
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+// secretUpdate returns the update that leaves the secret as it is.
+func secretUpdate(secret *sdk.SecretResponse) *sdk.SecretPutRequest {
+	update := &sdk.SecretPutRequest{
+		ID:             secret.ID,
+		Key:            secret.Key,
+		Value:          secret.Value,
+		Note:           secret.Note,
+		OrganizationID: secret.OrganizationID,
+	}
+	if secret.ProjectID != nil {
+		update.ProjectIDS = []string{*secret.ProjectID}
+	}
+
+	return update
+}
+
+// apply returns the update of the secret with the patch applied.
+func (p *PatchSecretRequest) apply(secret *sdk.SecretResponse) *sdk.SecretPutRequest {
+	update := secretUpdate(secret)
+	if p.Key != nil {
+		update.Key = *p.Key
+	}
+	if p.Value != nil {
+		update.Value = *p.Value
+	}
+	if p.Note != nil {
+		update.Note = *p.Note
+	}
+	if p.OrganizationID != nil {
+		update.OrganizationID = *p.OrganizationID
+	}
+	if p.ProjectIDs != nil {
+		update.ProjectIDS = *p.ProjectIDs
+	}
+
+	return update
+}
+
+// PatchSecret applies the patch to the current secret, fetched from Bitwarden rather than the
+// cache. Patches that don't change anything don't update the secret.
+func (svc *service) PatchSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *PatchSecretRequest) (*sdk.SecretResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs = "secret.patch", []string{request.ID}
+	})
+
+	if request.ID == "" {
+		return nil, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing id")
+	}
+
+	current, err := c.Secrets().Get(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	if err := checkETag(request.IfMatch, current); err != nil {
+		return nil, err
+	}
+
+	update := request.apply(current)
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.OrganizationID, e.ProjectIDs = update.OrganizationID, update.ProjectIDS
+	})
+
+	if unchanged := secretUpdate(current); update.Key == unchanged.Key && update.Value == unchanged.Value &&
+		update.Note == unchanged.Note && update.OrganizationID == unchanged.OrganizationID &&
+		sameProjects(update.ProjectIDS, unchanged.ProjectIDS) {
+		return current, nil
+	}
+
+	response, err := c.Secrets().Update(update.ID, update.Key, update.Value, update.Note, update.OrganizationID, update.ProjectIDS)
+	svc.invalidateSecrets(update.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return response, nil
+}
+
+func (s *Server) patchSecretHandler(w http.ResponseWriter, r *http.Request) {
+	request := &PatchSecretRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+	request.IfMatch = ifMatchHeader(r)
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanPatchSecret(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.PatchSecret(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
+}
+
+func (s *Server) patchSecretV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &PatchSecretRequest{}
+	if err := decodeBody(r, request); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := pathID(r, &request.ID); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+	request.IfMatch = ifMatchHeader(r)
+
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanPatchSecret(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.PatchSecret(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
+}
@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+func TestPatchSecret(t *testing.T) {
+	tests := []struct {
+		name            string
+		v1              bool
+		patch           string
+		ifMatch         string
+		expectedStatus  int
+		expectedError   string
+		expectedKey     string
+		expectedValue   string
+		expectedNote    string
+		expectedProject bool
+		expectedUpdate  bool
+	}{
+		{name: "value", patch: `{"value": "changed"}`, expectedStatus: http.StatusOK, expectedKey: "key", expectedValue: "changed", expectedNote: "note", expectedProject: true, expectedUpdate: true},
+		{name: "v1", v1: true, patch: `{"key": "renamed"}`, expectedStatus: http.StatusOK, expectedKey: "renamed", expectedValue: "value", expectedNote: "note", expectedProject: true, expectedUpdate: true},
+		{name: "clear note and projects", patch: `{"note": null, "projectIds": null}`, expectedStatus: http.StatusOK, expectedKey: "key", expectedValue: "value", expectedUpdate: true},
+		{name: "unchanged", patch: `{"value": "value"}`, expectedStatus: http.StatusOK, expectedKey: "key", expectedValue: "value", expectedNote: "note", expectedProject: true},
+		{name: "empty", patch: `{}`, expectedStatus: http.StatusOK, expectedKey: "key", expectedValue: "value", expectedNote: "note", expectedProject: true},
+		{name: "if match", patch: `{"value": "changed"}`, ifMatch: "*", expectedStatus: http.StatusOK, expectedKey: "key", expectedValue: "changed", expectedNote: "note", expectedProject: true, expectedUpdate: true},
+		{name: "if match mismatch", patch: `{"value": "changed"}`, ifMatch: `"other"`, expectedStatus: http.StatusPreconditionFailed, expectedError: "was modified"},
+		{name: "null key", patch: `{"key": null}`, expectedStatus: http.StatusBadRequest, expectedError: "key: can't be null"},
+		{name: "wrong type", patch: `{"value": 42}`, expectedStatus: http.StatusBadRequest, expectedError: "value: json: cannot unmarshal number"},
+		{name: "wrong project type", patch: `{"projectIds": "project"}`, expectedStatus: http.StatusBadRequest, expectedError: "projectIds: json: cannot unmarshal string"},
+		{name: "unknown field", patch: `{"values": "changed"}`, expectedStatus: http.StatusBadRequest, expectedError: "values: unknown field"},
+		{name: "not an object", patch: `["value"]`, expectedStatus: http.StatusBadRequest, expectedError: "invalid request body"},
+		{name: "mismatching id", patch: `{"id": "other", "value": "changed"}`, expectedStatus: http.StatusBadRequest, expectedError: "doesn't match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newMemoryClient(t)
+			project, err := client.Projects().Create("org-1", "project")
+			require.NoError(t, err)
+			secret, err := client.Secrets().Create("key", "value", "note", "org-1", []string{project.ID})
+			require.NoError(t, err)
+
+			path, body := apiV2+"/secrets/"+secret.ID, tt.patch
+			if tt.v1 {
+				var fields map[string]any
+				require.NoError(t, json.Unmarshal([]byte(tt.patch), &fields))
+				fields["id"] = secret.ID
+				content, err := json.Marshal(fields)
+				require.NoError(t, err)
+				path, body = api+"/secret", string(content)
+			}
+			req := httptest.NewRequest(http.MethodPatch, path, bytes.NewBufferString(body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			w := serveRequestWithClient(NewServer(Config{}), client, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedError != "" {
+				var body apierror.Body
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Contains(t, body.Message, tt.expectedError)
+
+				return
+			}
+
+			var response sdk.SecretResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			stored, err := client.Secrets().Get(secret.ID)
+			require.NoError(t, err)
+			assert.Equal(t, *stored, response)
+			assert.Equal(t, secretETag(stored), w.Header().Get("ETag"))
+
+			assert.Equal(t, tt.expectedKey, stored.Key)
+			assert.Equal(t, tt.expectedValue, stored.Value)
+			assert.Equal(t, tt.expectedNote, stored.Note)
+			assert.Equal(t, tt.expectedProject, stored.ProjectID != nil)
+			assert.Equal(t, tt.expectedUpdate, stored.RevisionDate.After(secret.RevisionDate))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+func (s *Server) getProjectHandler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectGetRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GetProject(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) listProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	request := &ListProjectsRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.ListProjects(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) deleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectsDeleteRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanDeleteProjects(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.DeleteProjects(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) createProjectHandler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectCreateRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanCreateProject(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.CreateProject(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) updateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectPutRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpdateProject(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpdateProject(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
@@ -0,0 +1,235 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+type mockProjects struct {
+	getResp    *sdk.ProjectResponse
+	getErr     error
+	listResp   *sdk.ProjectsResponse
+	listErr    error
+	deleteResp *sdk.ProjectsDeleteResponse
+	deleteErr  error
+	createResp *sdk.ProjectResponse
+	createErr  error
+	updateResp *sdk.ProjectResponse
+	updateErr  error
+}
+
+var _ sdk.ProjectsInterface = &mockProjects{}
+
+func (m *mockProjects) Get(id string) (*sdk.ProjectResponse, error) {
+	return m.getResp, m.getErr
+}
+
+func (m *mockProjects) List(orgID string) (*sdk.ProjectsResponse, error) {
+	return m.listResp, m.listErr
+}
+
+func (m *mockProjects) Delete(ids []string) (*sdk.ProjectsDeleteResponse, error) {
+	return m.deleteResp, m.deleteErr
+}
+
+func (m *mockProjects) Create(orgID, name string) (*sdk.ProjectResponse, error) {
+	return m.createResp, m.createErr
+}
+
+func (m *mockProjects) Update(id, orgID, name string) (*sdk.ProjectResponse, error) {
+	return m.updateResp, m.updateErr
+}
+
+func TestProjectHandlers(t *testing.T) {
+	s := NewServer(Config{})
+
+	tests := []struct {
+		name           string
+		handler        func(w http.ResponseWriter, r *http.Request)
+		method         string
+		path           string
+		body           string
+		projects       *mockProjects
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "get success",
+			handler: s.getProjectHandler,
+			method:  http.MethodGet,
+			path:    "/project",
+			body:    `{"id": "proj-1"}`,
+			projects: &mockProjects{
+				getResp: &sdk.ProjectResponse{ID: "proj-1", Name: "project"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get error",
+			handler:        s.getProjectHandler,
+			method:         http.MethodGet,
+			path:           "/project",
+			body:           `{"id": "proj-1"}`,
+			projects:       &mockProjects{getErr: errors.New("project not found")},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": "not_found", "message": "failed to get project: project not found", "retryable": false}`,
+		},
+		{
+			name:    "list success",
+			handler: s.listProjectsHandler,
+			method:  http.MethodGet,
+			path:    "/projects",
+			body:    `{"organizationId": "org-1"}`,
+			projects: &mockProjects{
+				listResp: &sdk.ProjectsResponse{
+					Data: []sdk.ProjectResponse{{ID: "proj-1", Name: "project"}},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "list error",
+			handler:        s.listProjectsHandler,
+			method:         http.MethodGet,
+			path:           "/projects",
+			body:           `{"organizationId": "org-1"}`,
+			projects:       &mockProjects{listErr: errors.New("list failed")},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": "bad_request", "message": "failed to list projects: list failed", "retryable": false}`,
+		},
+		{
+			name:    "delete success",
+			handler: s.deleteProjectHandler,
+			method:  http.MethodDelete,
+			path:    "/project",
+			body:    `{"ids": ["proj-1"]}`,
+			projects: &mockProjects{
+				deleteResp: &sdk.ProjectsDeleteResponse{
+					Data: []sdk.ProjectDeleteResponse{{ID: "proj-1"}},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "delete error",
+			handler:        s.deleteProjectHandler,
+			method:         http.MethodDelete,
+			path:           "/project",
+			body:           `{"ids": ["proj-1"]}`,
+			projects:       &mockProjects{deleteErr: errors.New("delete failed")},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": "bad_request", "message": "failed to delete projects: delete failed", "retryable": false}`,
+		},
+		{
+			name:    "create success",
+			handler: s.createProjectHandler,
+			method:  http.MethodPost,
+			path:    "/project",
+			body:    `{"organizationId": "org-1", "name": "project"}`,
+			projects: &mockProjects{
+				createResp: &sdk.ProjectResponse{ID: "proj-1", Name: "project"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "create error",
+			handler:        s.createProjectHandler,
+			method:         http.MethodPost,
+			path:           "/project",
+			body:           `{"organizationId": "org-1", "name": "project"}`,
+			projects:       &mockProjects{createErr: errors.New("create failed")},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": "bad_request", "message": "failed to create project: create failed", "retryable": false}`,
+		},
+		{
+			name:    "update success",
+			handler: s.updateProjectHandler,
+			method:  http.MethodPut,
+			path:    "/project",
+			body:    `{"id": "proj-1", "organizationId": "org-1", "name": "renamed"}`,
+			projects: &mockProjects{
+				updateResp: &sdk.ProjectResponse{ID: "proj-1", Name: "renamed"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "update error",
+			handler:        s.updateProjectHandler,
+			method:         http.MethodPut,
+			path:           "/project",
+			body:           `{"id": "proj-1", "organizationId": "org-1", "name": "renamed"}`,
+			projects:       &mockProjects{updateErr: errors.New("update failed")},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code": "bad_request", "message": "failed to update project: update failed", "retryable": false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), bitwarden.ContextClientKey, &mockClient{projects: tt.projects})
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			tt.handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestProjectHandlersWithInvalidBody(t *testing.T) {
+	s := NewServer(Config{})
+	client := &mockClient{projects: &mockProjects{}}
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		method  string
+	}{
+		{"getProjectHandler", s.getProjectHandler, http.MethodGet},
+		{"listProjectsHandler", s.listProjectsHandler, http.MethodGet},
+		{"deleteProjectHandler", s.deleteProjectHandler, http.MethodDelete},
+		{"createProjectHandler", s.createProjectHandler, http.MethodPost},
+		{"updateProjectHandler", s.updateProjectHandler, http.MethodPut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"_invalid_json", func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/project", bytes.NewBufferString("{invalid"))
+			ctx := context.WithValue(req.Context(), bitwarden.ContextClientKey, client)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			tt.handler(w, req)
+
+			assert.True(t, w.Code >= 400)
+		})
+	}
+}
@@ -18,20 +18,29 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log"
 	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/bitwarden/sdk-go/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
 
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
 	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/metrics"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/tracing"
 )
 
 const (
-	api = "/rest/api/1"
+	api   = "/rest/api/1"
+	apiV2 = "/rest/api/2"
 )
 
 type Config struct {
@@ -40,22 +49,163 @@ type Config struct {
 	Addr     string
 	KeyFile  string
 	CertFile string
+
+	// Backend provides the clients requests are served with. If nil, the server logs in to
+	// Bitwarden using the SDK.
+	Backend bitwarden.Backend
+
+	// SessionTTL defines how long an authenticated client is kept around after its last use.
+	// Zero disables session reuse and every request logs in again.
+	SessionTTL time.Duration
+	// SessionMaxSize limits the number of cached sessions.
+	SessionMaxSize int
+
+	// AllowedHosts restricts the hosts callers can point the server to using the Warden URL headers.
+	AllowedHosts []string
+	// RequireHTTPS rejects Warden URL headers not using https.
+	RequireHTTPS bool
+	// PinURLs ignores the Warden URL headers and always uses APIURL and IdentityURL.
+	PinURLs     bool
+	APIURL      string
+	IdentityURL string
+
+	// StateDir is the directory all Warden-State-Path values are resolved in. If empty, state
+	// paths are used as provided.
+	StateDir string
+	// DeriveStatePath derives the state path from the access token if Warden-State-Path is not set.
+	DeriveStatePath bool
+
+	// SecretCacheTTL defines how long fetched secrets are served from memory. Zero disables the cache.
+	SecretCacheTTL time.Duration
+	// SecretCacheStaleTTL defines how long expired secrets are still served while being refreshed
+	// in the background.
+	SecretCacheStaleTTL time.Duration
+
+	// BulkConcurrency is the number of items of a bulk request processed at once.
+	BulkConcurrency int
+
+	// MetricsAddr is the address of a separate plain http listener serving /metrics. If empty,
+	// /metrics is served next to the API.
+	MetricsAddr string
+
+	// GRPCAddr is the address of the gRPC API. It uses the same TLS configuration as the REST API.
+	// If empty, gRPC is disabled.
+	GRPCAddr string
+	// GRPCReflection serves the schema of the gRPC API through the reflection service.
+	GRPCReflection bool
+
+	// Audit configures the audit log of secret access and changes. Disabled if no path is set.
+	Audit audit.Config
 }
 
 // Server defines a server which runs and accepts requests.
 type Server struct {
 	Config
 
-	server *http.Server
+	server   *http.Server
+	admin    *http.Server
+	grpc     *grpc.Server
+	sessions *bitwarden.SessionPool
+	svc      *service
+	warden   bitwarden.WardenOptions
+	auditLog *audit.Logger
+}
+
+// Validate rejects combinations of options that can't be honored.
+func (c *Config) Validate() error {
+	if c.DeriveStatePath && c.StateDir == "" {
+		return errors.New("deriving state paths requires a state directory")
+	}
+
+	return nil
 }
 
 func NewServer(cfg Config) *Server {
-	return &Server{Config: cfg}
+	s := &Server{Config: cfg}
+	if cfg.SessionTTL > 0 {
+		s.sessions = bitwarden.NewSessionPool(cfg.Backend, cfg.SessionTTL, cfg.SessionMaxSize)
+	}
+
+	s.svc = &service{bulkConcurrency: cfg.BulkConcurrency}
+	if cfg.SecretCacheTTL > 0 {
+		s.svc.cache = newSecretCache(cfg.SecretCacheTTL, cfg.SecretCacheStaleTTL)
+	}
+
+	s.warden = bitwarden.WardenOptions{
+		Backend:  cfg.Backend,
+		Sessions: s.sessions,
+		URLPolicy: &bitwarden.URLPolicy{
+			AllowedHosts: cfg.AllowedHosts,
+			RequireHTTPS: cfg.RequireHTTPS,
+			PinURLs:      cfg.PinURLs,
+			APIURL:       cfg.APIURL,
+			IdentityURL:  cfg.IdentityURL,
+		},
+		StatePolicy: &bitwarden.StatePolicy{
+			Dir:        cfg.StateDir,
+			DerivePath: cfg.DeriveStatePath,
+		},
+	}
+
+	return s
 }
 
 func (s *Server) Run(_ context.Context) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.warden.StatePolicy.CreateDir(); err != nil {
+		return err
+	}
+
+	if s.Audit.Path != "" {
+		l, err := audit.New(s.Audit)
+		if err != nil {
+			return err
+		}
+		s.auditLog = l
+	}
+
+	if s.GRPCAddr != "" {
+		if err := s.serveGRPC(); err != nil {
+			return err
+		}
+	}
+
+	srv := &http.Server{Addr: s.Addr, Handler: s.routes(), ReadTimeout: 5 * time.Second}
+	s.server = srv
+
+	if s.MetricsAddr != "" {
+		s.admin = &http.Server{Addr: s.MetricsAddr, Handler: s.adminRoutes(), ReadTimeout: 5 * time.Second}
+		go func() {
+			slog.Info("starting admin listener on http", "addr", s.MetricsAddr)
+			if err := s.admin.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("admin listener stopped unexpectedly", "error", err)
+			}
+		}()
+	}
+
+	if s.Insecure {
+		slog.Info("starting to listen on http", "addr", s.Addr)
+		return srv.ListenAndServe()
+	}
+
+	return srv.ListenAndServeTLS(s.CertFile, s.KeyFile)
+}
+
+// routes returns the router serving the API.
+func (s *Server) routes() chi.Router {
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	r.Use(tracing.Middleware)
+	r.Use(metrics.Middleware)
+	r.Use(middleware.RequestID)
+	if s.Audit.Path == audit.Stdout {
+		// Keep stdout to the audit log, the request log goes to stderr instead.
+		r.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{Logger: log.New(os.Stderr, "", log.LstdFlags), NoColor: true}))
+	} else {
+		r.Use(middleware.Logger)
+	}
 	r.Use(middleware.Recoverer)
 	r.Get("/ready", func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = w.Write([]byte("ready"))
@@ -63,32 +213,95 @@ func (s *Server) Run(_ context.Context) error {
 	r.Get("/live", func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = w.Write([]byte("live"))
 	})
+	r.Get(openAPIPath, s.openAPIHandler)
 
+	r.Mount(api, s.wardenRouter(s.v1Routes))
+	r.Mount(apiV2, s.wardenRouter(s.v2Routes))
+
+	if s.MetricsAddr == "" {
+		r.Method(http.MethodGet, "/metrics", metrics.Handler())
+	}
+
+	return r
+}
+
+// wardenRouter returns a router authenticating every request with the Warden.
+func (s *Server) wardenRouter(register func(r chi.Router)) chi.Router {
 	warden := chi.NewRouter()
-	warden.Use(bitwarden.Warden)
+	warden.Use(audit.Middleware(s.auditLog))
+	warden.Use(bitwarden.NewWarden(s.warden))
+	register(warden)
 
+	return warden
+}
+
+// v1Routes registers the routes of the v1 API, which expects every request in a JSON body.
+func (s *Server) v1Routes(warden chi.Router) {
 	// The header will always contain the right credentials.
 	warden.Get("/secret", s.getSecretHandler)
+	warden.Get("/secret-by-key", s.getSecretByKeyHandler)
 	warden.Get("/secrets", s.listSecretsHandler)
 	warden.Get("/secrets-by-ids", s.getByIdsSecretHandler)
+	warden.Get("/secrets/sync", s.syncSecretsHandler)
 	warden.Delete("/secret", s.deleteSecretHandler)
 	warden.Post("/secret", s.createSecretHandler)
 	warden.Put("/secret", s.updateSecretHandler)
+	warden.Patch("/secret", s.patchSecretHandler)
+	warden.Put("/secret-by-key", s.upsertSecretHandler)
+	warden.Post("/secrets/bulk", s.createSecretsHandler)
+	warden.Put("/secrets/bulk", s.updateSecretsHandler)
+	warden.Get("/export", s.exportSecretsHandler)
+
+	warden.Get("/project", s.getProjectHandler)
+	warden.Get("/projects", s.listProjectsHandler)
+	warden.Delete("/project", s.deleteProjectHandler)
+	warden.Post("/project", s.createProjectHandler)
+	warden.Put("/project", s.updateProjectHandler)
+
+	warden.Post("/generators/password", s.generatePasswordHandler)
+}
 
-	r.Mount(api, warden)
+// adminRoutes returns the router of the admin listener.
+func (s *Server) adminRoutes() chi.Router {
+	r := chi.NewRouter()
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
 
-	srv := &http.Server{Addr: s.Addr, Handler: r, ReadTimeout: 5 * time.Second}
-	s.server = srv
+	return r
+}
 
-	if s.Insecure {
-		slog.Info("starting to listen on http", "addr", s.Addr)
-		return srv.ListenAndServe()
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.sessions != nil {
+		defer s.sessions.Close()
 	}
 
-	return srv.ListenAndServeTLS(s.CertFile, s.KeyFile)
-}
+	if s.auditLog != nil {
+		defer func() {
+			if err := s.auditLog.Close(); err != nil {
+				slog.Error("failed to close audit log", "error", err)
+			}
+		}()
+	}
+
+	if s.grpc != nil {
+		stopped := make(chan struct{})
+		go func() {
+			s.grpc.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			s.grpc.Stop()
+		}
+	}
+
+	if s.admin != nil {
+		if err := s.admin.Shutdown(ctx); err != nil {
+			slog.Error("failed to shut down admin listener", "error", err)
+		}
+	}
 
-func (s *Server) Shutdown(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
 }
 
@@ -96,152 +309,193 @@ func (s *Server) getSecretHandler(w http.ResponseWriter, r *http.Request) {
 	request := &sdk.SecretGetRequest{}
 	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	secretResponse, err := c.Secrets().Get(request.ID)
-	if err != nil {
-		http.Error(w, "failed to get secret: "+err.Error(), http.StatusBadRequest)
-
-		return
-	}
-
-	s.handleResponse(secretResponse, w)
+	response, err := s.svc.GetSecret(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
 }
 
 func (s *Server) getByIdsSecretHandler(w http.ResponseWriter, r *http.Request) {
 	request := &sdk.SecretsGetRequest{}
 	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	secretResponse, err := c.Secrets().GetByIDS(request.IDS)
-	if err != nil {
-		http.Error(w, "failed to get secrets: "+err.Error(), http.StatusBadRequest)
-
-		return
-	}
-
-	s.handleResponse(secretResponse, w)
+	response, err := s.svc.GetSecretsByIDs(r.Context(), c, request)
+	s.respond(w, r, response, err)
 }
 
 func (s *Server) listSecretsHandler(w http.ResponseWriter, r *http.Request) {
-	request := &sdk.SecretIdentifiersRequest{}
+	request := &ListSecretsRequest{}
 	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	secretResponse, err := c.Secrets().List(request.OrganizationID)
+	response, err := s.svc.ListSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+// syncSecretsHandler returns the secrets of an organization that changed since the
+// optional last synced date. If no date is provided all secrets are returned.
+func (s *Server) syncSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.SecretsSyncRequest{}
+	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, "failed to get secret: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	s.handleResponse(secretResponse, w)
+	response, err := s.svc.SyncSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
 }
 
 func (s *Server) deleteSecretHandler(w http.ResponseWriter, r *http.Request) {
 	request := &sdk.SecretsDeleteRequest{}
 	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	response, err := c.Secrets().Delete(request.IDS)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := checkIfMatch(r, c, request.IDS); err != nil {
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	s.handleResponse(response, w)
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanDeleteSecrets(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.DeleteSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
 }
 
 func (s *Server) createSecretHandler(w http.ResponseWriter, r *http.Request) {
 	request := &sdk.SecretCreateRequest{}
 	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	response, err := c.Secrets().Create(request.Key, request.Value, request.Note, request.OrganizationID, request.ProjectIDS)
-	if err != nil {
-		http.Error(w, "failed to create secret: "+err.Error(), http.StatusBadRequest)
-
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanCreateSecret(r.Context(), c, request, opts)
+	}) {
 		return
 	}
 
-	s.handleResponse(response, w)
+	response, err := s.svc.CreateSecret(r.Context(), c, request)
+	s.respond(w, r, response, err)
 }
 
 func (s *Server) updateSecretHandler(w http.ResponseWriter, r *http.Request) {
 	request := &sdk.SecretPutRequest{}
 	c, err := s.getClient(r, &request)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	response, err := c.Secrets().Update(request.ID, request.Key, request.Value, request.Note, request.OrganizationID, request.ProjectIDS)
-	if err != nil {
-		http.Error(w, "failed to update secret: "+err.Error(), http.StatusBadRequest)
+	if err := checkIfMatch(r, c, []string{request.ID}); err != nil {
+		apierror.Write(w, r, err)
 
 		return
 	}
 
-	s.handleResponse(response, w)
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpdateSecret(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpdateSecret(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
 }
 
+// getClient decodes the JSON body of the request into response and returns the client
+// authenticated by the Warden.
 func (s *Server) getClient(r *http.Request, response any) (sdk.BitwardenClientInterface, error) {
+	if err := decodeBody(r, response); err != nil {
+		return nil, err
+	}
+
+	return s.client(r)
+}
+
+// decodeBody decodes the JSON body of the request into response.
+func decodeBody(r *http.Request, response any) error {
 	content, err := io.ReadAll(r.Body)
 	if err != nil {
-		return nil, err
+		return apierror.New(http.StatusBadRequest, apierror.CodeInvalidRequest, err)
 	}
 	defer func() {
 		_ = r.Body.Close()
 	}()
 
 	if err := json.Unmarshal(content, response); err != nil {
-		return nil, err
+		return apierror.New(http.StatusBadRequest, apierror.CodeInvalidRequest, fmt.Errorf("invalid request body: %w", err))
 	}
 
-	client := r.Context().Value(bitwarden.ContextClientKey)
+	return nil
+}
+
+// client returns the client authenticated by the Warden.
+func (s *Server) client(r *http.Request) (sdk.BitwardenClientInterface, error) {
+	return clientFromContext(r.Context())
+}
+
+// clientFromContext returns the client authenticated by the Warden, or by the gRPC equivalent.
+func clientFromContext(ctx context.Context) (sdk.BitwardenClientInterface, error) {
+	client := ctx.Value(bitwarden.ContextClientKey)
 	if client == nil {
-		return nil, errors.New("missing client in context, login error")
+		return nil, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, errors.New("missing client in context, login error"))
 	}
 
 	c, ok := client.(sdk.BitwardenClientInterface)
 	if !ok {
-		return nil, errors.New("invalid client in context, login error")
+		return nil, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, errors.New("invalid client in context, login error"))
 	}
 
 	return c, nil
 }
 
+// respond writes the response of a service call, or its error if it failed.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, response any, err error) {
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	s.handleResponse(response, w)
+}
+
 func (s *Server) handleResponse(response any, w http.ResponseWriter) {
 	body, err := json.Marshal(response)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.WriteStatus(w, nil, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 
 		return
 	}
 
 	if _, err := w.Write(body); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.WriteStatus(w, nil, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 
 		return
 	}
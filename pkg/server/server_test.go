@@ -32,16 +32,18 @@ import (
 )
 
 type mockClient struct {
-	secrets *mockSecrets
+	secrets    *mockSecrets
+	projects   *mockProjects
+	generators *mockGenerators
 }
 
 var _ sdk.BitwardenClientInterface = &mockClient{}
 
 func (m *mockClient) AccessTokenLogin(accessToken string, statePath *string) error { return nil }
-func (m *mockClient) Projects() sdk.ProjectsInterface                              { return nil }
+func (m *mockClient) Projects() sdk.ProjectsInterface                              { return m.projects }
 func (m *mockClient) Secrets() sdk.SecretsInterface                                { return m.secrets }
 func (m *mockClient) Close()                                                       {}
-func (m *mockClient) Generators() sdk.GeneratorsInterface                          { return nil }
+func (m *mockClient) Generators() sdk.GeneratorsInterface                          { return m.generators }
 
 type mockSecrets struct {
 	getResp      *sdk.SecretResponse
@@ -58,6 +60,8 @@ type mockSecrets struct {
 	updateErr    error
 	syncResp     *sdk.SecretsSyncResponse
 	syncErr      error
+
+	syncLastSyncedDate *time.Time
 }
 
 var _ sdk.SecretsInterface = &mockSecrets{}
@@ -87,6 +91,8 @@ func (m *mockSecrets) Update(id, key, value, note, orgID string, projectIDs []st
 }
 
 func (m *mockSecrets) Sync(orgID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	m.syncLastSyncedDate = lastSyncedDate
+
 	return m.syncResp, m.syncErr
 }
 
@@ -98,6 +104,48 @@ func TestNewServer(t *testing.T) {
 	s := NewServer(cfg)
 	assert.Equal(t, cfg.Addr, s.Addr)
 	assert.True(t, s.Insecure)
+	assert.Nil(t, s.sessions)
+}
+
+func TestNewServerWithSessions(t *testing.T) {
+	s := NewServer(Config{SessionTTL: time.Minute, SessionMaxSize: 10})
+	require.NotNil(t, s.sessions)
+	s.sessions.Close()
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, (&Config{}).Validate())
+	require.NoError(t, (&Config{StateDir: t.TempDir(), DeriveStatePath: true}).Validate())
+
+	cfg := Config{DeriveStatePath: true}
+	require.ErrorContains(t, cfg.Validate(), "requires a state directory")
+	require.ErrorContains(t, NewServer(cfg).Run(context.Background()), "requires a state directory")
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		routesStatus int
+		adminStatus  int
+	}{
+		{name: "served next to the api", cfg: Config{}, routesStatus: http.StatusOK, adminStatus: http.StatusOK},
+		{name: "served on the admin listener", cfg: Config{MetricsAddr: ":9999"}, routesStatus: http.StatusNotFound, adminStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(tt.cfg)
+
+			w := httptest.NewRecorder()
+			s.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+			assert.Equal(t, tt.routesStatus, w.Code)
+
+			w = httptest.NewRecorder()
+			s.adminRoutes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+			assert.Equal(t, tt.adminStatus, w.Code)
+		})
+	}
 }
 
 func TestReadyEndpoint(t *testing.T) {
@@ -156,8 +204,8 @@ func TestGetSecretHandler(t *testing.T) {
 					getErr: errors.New("secret not found"),
 				},
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "failed to get secret: secret not found\n",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code": "not_found", "message": "failed to get secret: secret not found", "retryable": false}`,
 		},
 	}
 
@@ -173,7 +221,7 @@ func TestGetSecretHandler(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
 			}
 		})
 	}
@@ -211,7 +259,7 @@ func TestGetByIdsSecretHandler(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "failed to get secrets: failed to fetch\n",
+			expectedBody:   `{"code": "bad_request", "message": "failed to get secrets: failed to fetch", "retryable": false}`,
 		},
 	}
 
@@ -227,7 +275,7 @@ func TestGetByIdsSecretHandler(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
 			}
 		})
 	}
@@ -264,7 +312,7 @@ func TestListSecretsHandler(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "failed to get secret: list failed\n",
+			expectedBody:   `{"code": "bad_request", "message": "failed to get secret: list failed", "retryable": false}`,
 		},
 	}
 
@@ -280,7 +328,75 @@ func TestListSecretsHandler(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSyncSecretsHandler(t *testing.T) {
+	lastSynced := time.Date(2024, 4, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		body               string
+		secrets            *mockSecrets
+		expectedStatus     int
+		expectedBody       string
+		expectedLastSynced *time.Time
+	}{
+		{
+			name: "success with last synced date",
+			body: `{"organizationId": "org-1", "lastSyncedDate": "2024-04-04T10:00:00Z"}`,
+			secrets: &mockSecrets{
+				syncResp: &sdk.SecretsSyncResponse{
+					HasChanges: true,
+					Secrets: []sdk.SecretResponse{
+						{ID: "id1", Key: "key1", Value: "value1"},
+					},
+				},
+			},
+			expectedStatus:     http.StatusOK,
+			expectedBody:       `"hasChanges":true`,
+			expectedLastSynced: &lastSynced,
+		},
+		{
+			name: "success without last synced date",
+			body: `{"organizationId": "org-1"}`,
+			secrets: &mockSecrets{
+				syncResp: &sdk.SecretsSyncResponse{HasChanges: false},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"hasChanges":false`,
+		},
+		{
+			name: "sync error",
+			body: `{"organizationId": "org-1"}`,
+			secrets: &mockSecrets{
+				syncErr: errors.New("sync failed"),
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "failed to sync secrets: sync failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(Config{})
+			req := httptest.NewRequest(http.MethodGet, "/secrets/sync", bytes.NewBufferString(tt.body))
+			ctx := context.WithValue(req.Context(), bitwarden.ContextClientKey, &mockClient{secrets: tt.secrets})
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			s.syncSecretsHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedLastSynced != nil {
+				require.NotNil(t, tt.secrets.syncLastSyncedDate)
+				assert.True(t, tt.expectedLastSynced.Equal(*tt.secrets.syncLastSyncedDate))
+			} else {
+				assert.Nil(t, tt.secrets.syncLastSyncedDate)
 			}
 		})
 	}
@@ -317,7 +433,7 @@ func TestDeleteSecretHandler(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "delete failed\n",
+			expectedBody:   `{"code": "bad_request", "message": "failed to delete secrets: delete failed", "retryable": false}`,
 		},
 	}
 
@@ -333,7 +449,7 @@ func TestDeleteSecretHandler(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
 			}
 		})
 	}
@@ -370,7 +486,7 @@ func TestCreateSecretHandler(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "failed to create secret: create failed\n",
+			expectedBody:   `{"code": "bad_request", "message": "failed to create secret: create failed", "retryable": false}`,
 		},
 	}
 
@@ -386,7 +502,7 @@ func TestCreateSecretHandler(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
 			}
 		})
 	}
@@ -423,7 +539,7 @@ func TestUpdateSecretHandler(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "failed to update secret: update failed\n",
+			expectedBody:   `{"code": "bad_request", "message": "failed to update secret: update failed", "retryable": false}`,
 		},
 	}
 
@@ -439,7 +555,7 @@ func TestUpdateSecretHandler(t *testing.T) {
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
 			if tt.expectedBody != "" {
-				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
 			}
 		})
 	}
@@ -561,6 +677,7 @@ func TestHandlerWithInvalidBody(t *testing.T) {
 	}{
 		{"getSecretHandler", s.getSecretHandler, http.MethodGet, "/secret"},
 		{"getByIdsSecretHandler", s.getByIdsSecretHandler, http.MethodGet, "/secrets-by-ids"},
+		{"syncSecretsHandler", s.syncSecretsHandler, http.MethodGet, "/secrets/sync"},
 		{"listSecretsHandler", s.listSecretsHandler, http.MethodGet, "/secrets"},
 		{"deleteSecretHandler", s.deleteSecretHandler, http.MethodDelete, "/secret"},
 		{"createSecretHandler", s.createSecretHandler, http.MethodPost, "/secret"},
@@ -576,7 +693,8 @@ func TestHandlerWithInvalidBody(t *testing.T) {
 
 			tt.handler(w, req)
 
-			assert.True(t, w.Code >= 400)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":"invalid_request"`)
 		})
 	}
 }
@@ -592,6 +710,7 @@ func TestHandlerWithNoClientInContext(t *testing.T) {
 	}{
 		{"getSecretHandler", s.getSecretHandler, http.MethodGet, "/secret"},
 		{"getByIdsSecretHandler", s.getByIdsSecretHandler, http.MethodGet, "/secrets-by-ids"},
+		{"syncSecretsHandler", s.syncSecretsHandler, http.MethodGet, "/secrets/sync"},
 		{"listSecretsHandler", s.listSecretsHandler, http.MethodGet, "/secrets"},
 		{"deleteSecretHandler", s.deleteSecretHandler, http.MethodDelete, "/secret"},
 		{"createSecretHandler", s.createSecretHandler, http.MethodPost, "/secret"},
@@ -605,7 +724,8 @@ func TestHandlerWithNoClientInContext(t *testing.T) {
 
 			tt.handler(w, req)
 
-			assert.True(t, w.Code >= 400)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Contains(t, w.Body.String(), `"code":"internal"`)
 		})
 	}
 }
@@ -0,0 +1,299 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+// service implements the operations of the server independent of the transport. The REST
+// handlers and the gRPC methods only translate requests and responses, so both behave the same.
+// Every operation describes itself in the audit event of the request.
+type service struct {
+	cache *secretCache
+	// bulkConcurrency is the number of items of a bulk request processed at once.
+	bulkConcurrency int
+}
+
+func (svc *service) GetSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretGetRequest) (*sdk.SecretResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs = "secret.get", []string{request.ID}
+	})
+
+	response, err := svc.getSecret(ctx, c, request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) GetSecretsByIDs(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretsGetRequest) (*sdk.SecretsResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs = "secrets.get", request.IDS
+	})
+
+	response, err := svc.getSecretsByIDs(ctx, c, request.IDS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secrets: %w", err)
+	}
+
+	return response, nil
+}
+
+// ListSecrets returns the identifiers of the secrets of an organization matching the filter of
+// the request, sorted and paginated. Bitwarden can't filter, so all identifiers are fetched and
+// filtered here. Values are only fetched for the secrets of the page, if requested.
+func (svc *service) ListSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *ListSecretsRequest) (*SecretsListResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID, e.ProjectIDs = "secrets.list", request.OrganizationID, request.ProjectIDs
+	})
+
+	match, err := request.matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	field, desc, err := request.sortField(sortByKey, sortByRevisionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	identifiers, err := c.Secrets().List(request.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	matched := make([]sdk.SecretIdentifierResponse, 0, len(identifiers.Data))
+	for _, identifier := range identifiers.Data {
+		if match(identifier) {
+			matched = append(matched, identifier)
+		}
+	}
+
+	// Identifiers don't have a revision date, sorting by it requires fetching the secrets. That
+	// costs as much as fetching all of them, so it is limited to few secrets.
+	var secrets []sdk.SecretResponse
+	if field == sortByRevisionDate && len(matched) > maxRevisionDateSortSecrets {
+		return nil, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "sorting by %s is limited to %d secrets, %d match, narrow the filter or sort by %s", sortByRevisionDate, maxRevisionDateSortSecrets, len(matched), sortByKey)
+	}
+	if field == sortByRevisionDate && len(matched) > 0 {
+		response, err := svc.getSecretsByIDs(ctx, c, secretIDs(matched))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secrets: %w", err)
+		}
+		secrets = response.Data
+	}
+
+	revisionDates := make(map[string]time.Time, len(secrets))
+	for _, secret := range secrets {
+		revisionDates[secret.ID] = secret.RevisionDate
+	}
+
+	page, token, err := paginate(matched, &request.Page, field, desc, func(identifier sdk.SecretIdentifierResponse) sortKey {
+		if field == sortByRevisionDate {
+			return sortKey{value: sortableTime(revisionDates[identifier.ID]), id: identifier.ID}
+		}
+
+		return sortKey{value: identifier.Key, id: identifier.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SecretsListResponse{Data: page, ContinuationToken: token}
+	if !request.WithValues || len(page) == 0 {
+		return response, nil
+	}
+
+	ids := secretIDs(page)
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.SecretIDs = ids
+	})
+
+	if secrets == nil {
+		fetched, err := svc.getSecretsByIDs(ctx, c, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secrets: %w", err)
+		}
+		secrets = fetched.Data
+	}
+	response.Secrets = orderSecrets(ids, secrets)
+
+	return response, nil
+}
+
+// SyncSecrets returns the secrets of an organization that changed since the optional last
+// synced date. If no date is provided all secrets are returned.
+func (svc *service) SyncSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretsSyncRequest) (*sdk.SecretsSyncResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID = "secrets.sync", request.OrganizationID
+	})
+
+	response, err := c.Secrets().Sync(request.OrganizationID, request.LastSyncedDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync secrets: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) DeleteSecrets(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretsDeleteRequest) (*sdk.SecretsDeleteResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs = "secrets.delete", request.IDS
+	})
+
+	response, err := c.Secrets().Delete(request.IDS)
+	svc.invalidateSecrets(request.IDS...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete secrets: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) CreateSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretCreateRequest) (*sdk.SecretResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID, e.ProjectIDs = "secret.create", request.OrganizationID, request.ProjectIDS
+	})
+
+	response, err := c.Secrets().Create(request.Key, request.Value, request.Note, request.OrganizationID, request.ProjectIDS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) UpdateSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.SecretPutRequest) (*sdk.SecretResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.SecretIDs, e.OrganizationID, e.ProjectIDs = "secret.update", []string{request.ID}, request.OrganizationID, request.ProjectIDS
+	})
+
+	response, err := c.Secrets().Update(request.ID, request.Key, request.Value, request.Note, request.OrganizationID, request.ProjectIDS)
+	svc.invalidateSecrets(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) GetProject(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.ProjectGetRequest) (*sdk.ProjectResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.ProjectIDs = "project.get", []string{request.ID}
+	})
+
+	response, err := c.Projects().Get(request.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return response, nil
+}
+
+// ListProjects returns the projects of an organization, sorted and paginated.
+func (svc *service) ListProjects(ctx context.Context, c sdk.BitwardenClientInterface, request *ListProjectsRequest) (*ProjectsListResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID = "projects.list", request.OrganizationID
+	})
+
+	field, desc, err := request.sortField(sortByName, sortByRevisionDate)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := c.Projects().List(request.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	page, token, err := paginate(projects.Data, &request.Page, field, desc, func(project sdk.ProjectResponse) sortKey {
+		if field == sortByRevisionDate {
+			return sortKey{value: sortableTime(project.RevisionDate), id: project.ID}
+		}
+
+		return sortKey{value: project.Name, id: project.ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectsListResponse{Data: page, ContinuationToken: token}, nil
+}
+
+func (svc *service) DeleteProjects(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.ProjectsDeleteRequest) (*sdk.ProjectsDeleteResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.ProjectIDs = "projects.delete", request.IDS
+	})
+
+	response, err := c.Projects().Delete(request.IDS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete projects: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) CreateProject(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.ProjectCreateRequest) (*sdk.ProjectResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID = "project.create", request.OrganizationID
+	})
+
+	response, err := c.Projects().Create(request.OrganizationID, request.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) UpdateProject(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.ProjectPutRequest) (*sdk.ProjectResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.ProjectIDs, e.OrganizationID = "project.update", []string{request.ID}, request.OrganizationID
+	})
+
+	response, err := c.Projects().Update(request.ID, request.OrganizationID, request.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return response, nil
+}
+
+func (svc *service) GeneratePassword(ctx context.Context, c sdk.BitwardenClientInterface, request *sdk.PasswordGeneratorRequest) (*PasswordResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation = "password.generate"
+	})
+
+	password, err := c.Generators().GeneratePassword(*request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	if password == nil {
+		return nil, apierror.Errorf(http.StatusInternalServerError, apierror.CodeInternal, "failed to generate password: empty response")
+	}
+
+	return &PasswordResponse{Password: *password}, nil
+}
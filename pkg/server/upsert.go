@@ -0,0 +1,186 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/bitwarden/sdk-go/v2"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/audit"
+)
+
+// Results of an upsert.
+const (
+	upsertCreated   = "created"
+	upsertUpdated   = "updated"
+	upsertUnchanged = "unchanged"
+)
+
+// UpsertSecretRequest creates a secret with the key, or updates the existing one. Existing secrets
+// are looked up in the projects, or in the whole organization if there are none.
+type UpsertSecretRequest struct {
+	OrganizationID string `json:"organizationId"`
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	Note           string `json:"note"`
+	// ProjectIDs are the projects of the secret. If empty, an existing secret keeps its projects.
+	ProjectIDs []string `json:"projectIds,omitempty"`
+}
+
+// UpsertSecretResponse contains the created or updated secret.
+type UpsertSecretResponse struct {
+	// Result is created, updated or unchanged, if the existing secret already matched the request.
+	Result string              `json:"result"`
+	Secret *sdk.SecretResponse `json:"secret"`
+}
+
+func (r *UpsertSecretRequest) validate() error {
+	if r.OrganizationID == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing organizationId")
+	}
+
+	if r.Key == "" {
+		return apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing key")
+	}
+
+	return nil
+}
+
+// UpsertSecret creates the secret if no secret in the scope of the request has its key, or updates
+// the one that has. Secrets that already match the request aren't updated, so repeating a request
+// doesn't change their revision date. Several secrets with the key are a conflict.
+func (svc *service) UpsertSecret(ctx context.Context, c sdk.BitwardenClientInterface, request *UpsertSecretRequest) (*UpsertSecretResponse, error) {
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.Operation, e.OrganizationID, e.ProjectIDs = "secret.upsert", request.OrganizationID, request.ProjectIDs
+	})
+
+	if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	scope := keyScope{organizationID: request.OrganizationID, projectIDs: request.ProjectIDs}
+	matches, err := findByKey(c, scope, request.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) > 1 {
+		return nil, ambiguousKeyError(scope, request.Key, matches)
+	}
+
+	if len(matches) == 0 {
+		response, err := c.Secrets().Create(request.Key, request.Value, request.Note, request.OrganizationID, request.ProjectIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secret: %w", err)
+		}
+
+		audit.Annotate(ctx, func(e *audit.Event) {
+			e.SecretIDs = []string{response.ID}
+		})
+
+		return &UpsertSecretResponse{Result: upsertCreated, Secret: response}, nil
+	}
+
+	id := matches[0].ID
+	audit.Annotate(ctx, func(e *audit.Event) {
+		e.SecretIDs = []string{id}
+	})
+
+	existing, err := c.Secrets().Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	projectIDs := request.ProjectIDs
+	if len(projectIDs) == 0 {
+		projectIDs = matches[0].ProjectIDS
+	}
+
+	if existing.Value == request.Value && existing.Note == request.Note && sameProjects(matches[0].ProjectIDS, projectIDs) {
+		return &UpsertSecretResponse{Result: upsertUnchanged, Secret: existing}, nil
+	}
+
+	response, err := c.Secrets().Update(id, request.Key, request.Value, request.Note, request.OrganizationID, projectIDs)
+	svc.invalidateSecrets(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return &UpsertSecretResponse{Result: upsertUpdated, Secret: response}, nil
+}
+
+// sameProjects reports whether both lists contain the same projects, in any order.
+func sameProjects(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+func (s *Server) upsertSecretHandler(w http.ResponseWriter, r *http.Request) {
+	request := &UpsertSecretRequest{}
+	c, err := s.getClient(r, &request)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpsertSecret(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpsertSecret(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) upsertSecretV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &UpsertSecretRequest{}
+	if err := decodeBody(r, request); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := pathOrganizationID(r, &request.OrganizationID); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpsertSecret(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpsertSecret(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
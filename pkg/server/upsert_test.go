@@ -0,0 +1,86 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertSecret(t *testing.T) {
+	client := newMemoryClient(t)
+	project, err := client.Projects().Create("org-1", "project")
+	require.NoError(t, err)
+	other, err := client.Projects().Create("org-1", "other")
+	require.NoError(t, err)
+	existing, err := client.Secrets().Create("existing", "value", "note", "org-1", []string{project.ID})
+	require.NoError(t, err)
+	for range 2 {
+		_, err := client.Secrets().Create("duplicate", "value", "", "org-1", []string{project.ID})
+		require.NoError(t, err)
+	}
+	s := NewServer(Config{})
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		expectedResult string
+		expectedID     string
+		expectedValue  string
+	}{
+		{name: "unchanged", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "key": "existing", "value": "value", "note": "note", "projectIds": ["` + project.ID + `"]}`, expectedStatus: http.StatusOK, expectedResult: upsertUnchanged, expectedID: existing.ID, expectedValue: "value"},
+		{name: "unchanged keeping projects", path: apiV2 + "/organizations/org-1/secrets/by-key", body: `{"key": "existing", "value": "value", "note": "note"}`, expectedStatus: http.StatusOK, expectedResult: upsertUnchanged, expectedID: existing.ID, expectedValue: "value"},
+		{name: "updated", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "key": "existing", "value": "changed", "note": "note", "projectIds": ["` + project.ID + `"]}`, expectedStatus: http.StatusOK, expectedResult: upsertUpdated, expectedID: existing.ID, expectedValue: "changed"},
+		{name: "updated again", path: apiV2 + "/organizations/org-1/secrets/by-key", body: `{"organizationId": "org-1", "key": "existing", "value": "changed again", "note": "note"}`, expectedStatus: http.StatusOK, expectedResult: upsertUpdated, expectedID: existing.ID, expectedValue: "changed again"},
+		{name: "created", path: apiV2 + "/organizations/org-1/secrets/by-key", body: `{"key": "new", "value": "new value", "projectIds": ["` + project.ID + `"]}`, expectedStatus: http.StatusOK, expectedResult: upsertCreated, expectedValue: "new value"},
+		{name: "created in other project", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "key": "existing", "value": "other value", "projectIds": ["` + other.ID + `"]}`, expectedStatus: http.StatusOK, expectedResult: upsertCreated, expectedValue: "other value"},
+		{name: "ambiguous", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "key": "duplicate", "value": "value"}`, expectedStatus: http.StatusConflict},
+		{name: "missing key", path: api + "/secret-by-key", body: `{"organizationId": "org-1", "value": "value"}`, expectedStatus: http.StatusBadRequest},
+		{name: "mismatching organization", path: apiV2 + "/organizations/org-1/secrets/by-key", body: `{"organizationId": "org-2", "key": "key"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveWithClient(s, client, http.MethodPut, tt.path, tt.body)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response UpsertSecretResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedResult, response.Result)
+			if tt.expectedID != "" {
+				assert.Equal(t, tt.expectedID, response.Secret.ID)
+			}
+
+			stored, err := client.Secrets().Get(response.Secret.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedValue, stored.Value)
+		})
+	}
+
+	// The secret updated without projects kept its project.
+	stored, err := client.Secrets().Get(existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, *stored.ProjectID)
+}
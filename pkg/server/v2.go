@@ -0,0 +1,376 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/apierror"
+)
+
+// v2Routes registers the routes of the v2 API. Reads and deletes take their parameters from
+// the path and query, so they work with clients and proxies dropping bodies of GET and DELETE
+// requests. Creates and updates still take a JSON body. Every route shares the SDK call logic
+// with its v1 counterpart.
+func (s *Server) v2Routes(warden chi.Router) {
+	warden.Get("/secrets", s.getSecretsByIDsV2Handler)
+	warden.Get("/secrets/{id}", s.getSecretV2Handler)
+	warden.Delete("/secrets", s.deleteSecretsV2Handler)
+	warden.Delete("/secrets/{id}", s.deleteSecretV2Handler)
+	warden.Post("/secrets", s.createSecretHandler)
+	warden.Put("/secrets/{id}", s.updateSecretV2Handler)
+	warden.Patch("/secrets/{id}", s.patchSecretV2Handler)
+	warden.Post("/secrets/bulk", s.createSecretsHandler)
+	warden.Put("/secrets/bulk", s.updateSecretsHandler)
+	warden.Get("/organizations/{orgId}/secrets", s.listSecretsV2Handler)
+	warden.Get("/organizations/{orgId}/secrets/sync", s.syncSecretsV2Handler)
+	warden.Get("/organizations/{orgId}/secrets/by-key", s.getSecretByKeyV2Handler)
+	warden.Put("/organizations/{orgId}/secrets/by-key", s.upsertSecretV2Handler)
+	warden.Get("/export", s.exportSecretsV2Handler)
+
+	warden.Get("/projects/{id}", s.getProjectV2Handler)
+	warden.Delete("/projects", s.deleteProjectsV2Handler)
+	warden.Delete("/projects/{id}", s.deleteProjectV2Handler)
+	warden.Post("/projects", s.createProjectHandler)
+	warden.Put("/projects/{id}", s.updateProjectV2Handler)
+	warden.Get("/organizations/{orgId}/projects", s.listProjectsV2Handler)
+
+	warden.Post("/generators/password", s.generatePasswordHandler)
+}
+
+func (s *Server) getSecretV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.SecretGetRequest{ID: chi.URLParam(r, "id")}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GetSecret(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
+}
+
+func (s *Server) getSecretsByIDsV2Handler(w http.ResponseWriter, r *http.Request) {
+	ids, err := queryIDs(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	request := &sdk.SecretsGetRequest{IDS: ids}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GetSecretsByIDs(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) listSecretsV2Handler(w http.ResponseWriter, r *http.Request) {
+	filter, err := queryFilter(r.URL.Query())
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	page, err := queryPage(r.URL.Query())
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	request := &ListSecretsRequest{OrganizationID: chi.URLParam(r, "orgId"), SecretsFilter: filter, Page: page}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.ListSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) syncSecretsV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.SecretsSyncRequest{OrganizationID: chi.URLParam(r, "orgId")}
+	if v := r.URL.Query().Get("lastSyncedDate"); v != "" {
+		lastSyncedDate, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			apierror.Write(w, r, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid lastSyncedDate: %w", err))
+
+			return
+		}
+		request.LastSyncedDate = &lastSyncedDate
+	}
+
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.SyncSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) deleteSecretV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.SecretsDeleteRequest{IDS: []string{chi.URLParam(r, "id")}}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := checkIfMatch(r, c, request.IDS); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanDeleteSecrets(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.DeleteSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) deleteSecretsV2Handler(w http.ResponseWriter, r *http.Request) {
+	ids, err := queryIDs(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	request := &sdk.SecretsDeleteRequest{IDS: ids}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := checkIfMatch(r, c, request.IDS); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanDeleteSecrets(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.DeleteSecrets(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) updateSecretV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.SecretPutRequest{}
+	if err := decodeBody(r, request); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := pathID(r, &request.ID); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := checkIfMatch(r, c, []string{request.ID}); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpdateSecret(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpdateSecret(r.Context(), c, request)
+	s.respondSecret(w, r, response, err)
+}
+
+func (s *Server) getProjectV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectGetRequest{ID: chi.URLParam(r, "id")}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.GetProject(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) listProjectsV2Handler(w http.ResponseWriter, r *http.Request) {
+	page, err := queryPage(r.URL.Query())
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	request := &ListProjectsRequest{OrganizationID: chi.URLParam(r, "orgId"), Page: page}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	response, err := s.svc.ListProjects(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) deleteProjectV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectsDeleteRequest{IDS: []string{chi.URLParam(r, "id")}}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanDeleteProjects(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.DeleteProjects(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) deleteProjectsV2Handler(w http.ResponseWriter, r *http.Request) {
+	ids, err := queryIDs(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	request := &sdk.ProjectsDeleteRequest{IDS: ids}
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanDeleteProjects(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.DeleteProjects(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+func (s *Server) updateProjectV2Handler(w http.ResponseWriter, r *http.Request) {
+	request := &sdk.ProjectPutRequest{}
+	if err := decodeBody(r, request); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if err := pathID(r, &request.ID); err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	c, err := s.client(r)
+	if err != nil {
+		apierror.Write(w, r, err)
+
+		return
+	}
+
+	if s.respondDryRun(w, r, func(opts dryRun) (*DryRunResponse, error) {
+		return s.svc.PlanUpdateProject(r.Context(), c, request, opts)
+	}) {
+		return
+	}
+
+	response, err := s.svc.UpdateProject(r.Context(), c, request)
+	s.respond(w, r, response, err)
+}
+
+// queryIDs returns the IDs of the `ids` query parameter. IDs are either comma separated, like
+// `?ids=a,b`, or passed as repeated parameters, like `?ids=a&ids=b`.
+func queryIDs(r *http.Request) ([]string, error) {
+	ids := queryValues(r.URL.Query(), "ids")
+	if len(ids) == 0 {
+		return nil, apierror.Errorf(http.StatusBadRequest, apierror.CodeInvalidRequest, "missing ids query parameter")
+	}
+
+	return ids, nil
+}
+
+// pathID sets id to the id path parameter. An id already set from the body has to match it.
+func pathID(r *http.Request, id *string) error {
+	return pathParam(r, "id", "id", id)
+}
+
+// pathOrganizationID sets id to the orgId path parameter. An organizationId already set from the
+// body has to match it.
+func pathOrganizationID(r *http.Request, id *string) error {
+	return pathParam(r, "orgId", "organizationId", id)
+}
+
+// pathParam sets value to the path parameter. A value already set from the body field has to
+// match it.
+func pathParam(r *http.Request, param, field string, value *string) error {
+	v := chi.URLParam(r, param)
+	if *value != "" && *value != v {
+		return apierror.New(http.StatusBadRequest, apierror.CodeInvalidRequest, fmt.Errorf("%s %q in body doesn't match %s %q in path", field, *value, param, v))
+	}
+	*value = v
+
+	return nil
+}
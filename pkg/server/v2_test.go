@@ -0,0 +1,165 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitwarden/sdk-go/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/external-secrets/bitwarden-sdk-server/pkg/bitwarden"
+)
+
+// recordingSecrets records the SDK calls made with their arguments.
+type recordingSecrets struct {
+	mockSecrets
+
+	call string
+}
+
+func (m *recordingSecrets) Get(id string) (*sdk.SecretResponse, error) {
+	m.call = fmt.Sprintf("Get(%s)", id)
+
+	return &sdk.SecretResponse{ID: id}, nil
+}
+
+func (m *recordingSecrets) GetByIDS(ids []string) (*sdk.SecretsResponse, error) {
+	m.call = fmt.Sprintf("GetByIDS(%v)", ids)
+
+	return &sdk.SecretsResponse{}, nil
+}
+
+func (m *recordingSecrets) List(orgID string) (*sdk.SecretIdentifiersResponse, error) {
+	m.call = fmt.Sprintf("List(%s)", orgID)
+
+	return &sdk.SecretIdentifiersResponse{}, nil
+}
+
+func (m *recordingSecrets) Delete(ids []string) (*sdk.SecretsDeleteResponse, error) {
+	m.call = fmt.Sprintf("Delete(%v)", ids)
+
+	return &sdk.SecretsDeleteResponse{}, nil
+}
+
+func (m *recordingSecrets) Update(id, key, value, note, orgID string, projectIDs []string) (*sdk.SecretResponse, error) {
+	m.call = fmt.Sprintf("Update(%s, %s, %s, %s, %s, %v)", id, key, value, note, orgID, projectIDs)
+
+	return &sdk.SecretResponse{ID: id}, nil
+}
+
+func (m *recordingSecrets) Sync(orgID string, lastSyncedDate *time.Time) (*sdk.SecretsSyncResponse, error) {
+	m.call = fmt.Sprintf("Sync(%s, %v)", orgID, lastSyncedDate)
+
+	return &sdk.SecretsSyncResponse{}, nil
+}
+
+type recordingProjects struct {
+	mockProjects
+
+	call string
+}
+
+func (m *recordingProjects) Get(id string) (*sdk.ProjectResponse, error) {
+	m.call = fmt.Sprintf("Get(%s)", id)
+
+	return &sdk.ProjectResponse{ID: id}, nil
+}
+
+func (m *recordingProjects) List(orgID string) (*sdk.ProjectsResponse, error) {
+	m.call = fmt.Sprintf("List(%s)", orgID)
+
+	return &sdk.ProjectsResponse{}, nil
+}
+
+func (m *recordingProjects) Delete(ids []string) (*sdk.ProjectsDeleteResponse, error) {
+	m.call = fmt.Sprintf("Delete(%v)", ids)
+
+	return &sdk.ProjectsDeleteResponse{}, nil
+}
+
+func (m *recordingProjects) Update(id, orgID, name string) (*sdk.ProjectResponse, error) {
+	m.call = fmt.Sprintf("Update(%s, %s, %s)", id, orgID, name)
+
+	return &sdk.ProjectResponse{ID: id}, nil
+}
+
+// recordingClient is a client whose secrets and projects record the calls made.
+type recordingClient struct {
+	mockClient
+
+	secrets  *recordingSecrets
+	projects *recordingProjects
+}
+
+func (c *recordingClient) Secrets() sdk.SecretsInterface   { return c.secrets }
+func (c *recordingClient) Projects() sdk.ProjectsInterface { return c.projects }
+
+func TestV2Routes(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedCall   string
+	}{
+		{name: "get secret", method: http.MethodGet, path: "/secrets/id-1", expectedStatus: http.StatusOK, expectedCall: "Get(id-1)"},
+		{name: "get secrets by comma separated ids", method: http.MethodGet, path: "/secrets?ids=id-1,id-2", expectedStatus: http.StatusOK, expectedCall: "GetByIDS([id-1 id-2])"},
+		{name: "get secrets by repeated ids", method: http.MethodGet, path: "/secrets?ids=id-1&ids=id-2", expectedStatus: http.StatusOK, expectedCall: "GetByIDS([id-1 id-2])"},
+		{name: "get secrets without ids", method: http.MethodGet, path: "/secrets", expectedStatus: http.StatusBadRequest},
+		{name: "list secrets", method: http.MethodGet, path: "/organizations/org-1/secrets", expectedStatus: http.StatusOK, expectedCall: "List(org-1)"},
+		{name: "sync secrets", method: http.MethodGet, path: "/organizations/org-1/secrets/sync", expectedStatus: http.StatusOK, expectedCall: "Sync(org-1, <nil>)"},
+		{name: "sync secrets since", method: http.MethodGet, path: "/organizations/org-1/secrets/sync?lastSyncedDate=2024-01-02T03:04:05Z", expectedStatus: http.StatusOK, expectedCall: "Sync(org-1, 2024-01-02 03:04:05 +0000 UTC)"},
+		{name: "sync secrets with invalid date", method: http.MethodGet, path: "/organizations/org-1/secrets/sync?lastSyncedDate=yesterday", expectedStatus: http.StatusBadRequest},
+		{name: "delete secret", method: http.MethodDelete, path: "/secrets/id-1", expectedStatus: http.StatusOK, expectedCall: "Delete([id-1])"},
+		{name: "delete secrets", method: http.MethodDelete, path: "/secrets?ids=id-1,id-2", expectedStatus: http.StatusOK, expectedCall: "Delete([id-1 id-2])"},
+		{name: "update secret", method: http.MethodPut, path: "/secrets/id-1", body: `{"key": "key", "value": "value", "note": "note", "organizationId": "org-1", "projectIds": ["proj-1"]}`, expectedStatus: http.StatusOK, expectedCall: "Update(id-1, key, value, note, org-1, [proj-1])"},
+		{name: "update secret with mismatching id", method: http.MethodPut, path: "/secrets/id-1", body: `{"id": "id-2", "key": "key"}`, expectedStatus: http.StatusBadRequest},
+		{name: "get project", method: http.MethodGet, path: "/projects/proj-1", expectedStatus: http.StatusOK, expectedCall: "Get(proj-1)"},
+		{name: "list projects", method: http.MethodGet, path: "/organizations/org-1/projects", expectedStatus: http.StatusOK, expectedCall: "List(org-1)"},
+		{name: "delete project", method: http.MethodDelete, path: "/projects/proj-1", expectedStatus: http.StatusOK, expectedCall: "Delete([proj-1])"},
+		{name: "delete projects", method: http.MethodDelete, path: "/projects?ids=proj-1&ids=proj-2", expectedStatus: http.StatusOK, expectedCall: "Delete([proj-1 proj-2])"},
+		{name: "update project", method: http.MethodPut, path: "/projects/proj-1", body: `{"organizationId": "org-1", "name": "renamed"}`, expectedStatus: http.StatusOK, expectedCall: "Update(proj-1, org-1, renamed)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingClient{secrets: &recordingSecrets{}, projects: &recordingProjects{}}
+
+			r := chi.NewRouter()
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					ctx, done := bitwarden.WithClient(r.Context(), client, "identity", func() {})
+					defer done()
+					next.ServeHTTP(w, r.WithContext(ctx))
+				})
+			})
+			NewServer(Config{}).v2Routes(r)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			assert.Equal(t, tt.expectedCall, client.secrets.call+client.projects.call)
+		})
+	}
+}